@@ -0,0 +1,108 @@
+package contextlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type adminHandler struct {
+	logger *ContextLogger
+}
+
+// adminRequest changes one extractor. Omitted members are left unchanged.
+type adminRequest struct {
+	Name    string            `json:"name"`
+	Enabled *bool             `json:"enabled"`
+	Options map[string]string `json:"options"`
+}
+
+type adminResponse struct {
+	Extractors []ExtractorStats `json:"extractors"`
+}
+
+type adminError struct {
+	Error string `json:"error"`
+}
+
+// NewAdminHandler returns an http.Handler for inspecting and changing the
+// extractors of logger at runtime, like zap.AtomicLevel does for the level.
+//
+// GET responds with the extractors in the order they run:
+//
+//	{"extractors":[{"name":"otel","enabled":true,"calls":42,"avgCostNs":350,"reconfigurable":true}]}
+//
+// PUT changes the extractors with the given name and responds like GET. It
+// enables or disables them, rebuilds them with new options (see
+// ReconfigureExtractor), or both:
+//
+//	{"name":"headers","enabled":true}
+//	{"name":"value:request_id","options":{"default":"none"}}
+//
+// Errors respond with {"error":"..."}, and status 404 for an unknown name.
+func NewAdminHandler(logger *ContextLogger) http.Handler {
+	if logger == nil {
+		logger = nopLogger
+	}
+
+	return &adminHandler{logger: logger}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if status, err := h.update(r); err != nil {
+			writeAdminResponse(w, status, adminError{Error: err.Error()})
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeAdminResponse(w, http.StatusMethodNotAllowed, adminError{
+			Error: "Only GET and PUT are supported.",
+		})
+
+		return
+	}
+
+	writeAdminResponse(w, http.StatusOK, adminResponse{Extractors: h.logger.ExtractorStats()})
+}
+
+func (h *adminHandler) update(r *http.Request) (int, error) {
+	var req adminRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("request body must be a JSON object: %w", err)
+	}
+
+	if req.Name == "" {
+		return http.StatusBadRequest, errors.New("name is required")
+	}
+
+	if req.Enabled == nil && req.Options == nil {
+		return http.StatusBadRequest, errors.New("enabled or options is required")
+	}
+
+	if req.Options != nil {
+		if err := h.logger.ReconfigureExtractor(req.Name, req.Options); err != nil {
+			if errors.Is(err, ErrExtractorNotFound) {
+				return http.StatusNotFound, err
+			}
+
+			return http.StatusBadRequest, err
+		}
+	}
+
+	if req.Enabled != nil && !h.logger.SetEnabled(req.Name, *req.Enabled) {
+		return http.StatusNotFound, fmt.Errorf("%w: %q", ErrExtractorNotFound, req.Name)
+	}
+
+	return http.StatusOK, nil
+}
+
+func writeAdminResponse(w http.ResponseWriter, status int, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,157 @@
+package contextlogger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler(t *testing.T) {
+	ctx := context.WithValue(context.Background(), registryTestKey, "req-1")
+
+	newLogger := func(t *testing.T) *ContextLogger {
+		t.Helper()
+
+		logger, _ := newTestLogger()
+		cl, err := NewFromConfig(logger, Config{Extractors: []ExtractorConfig{
+			{Name: "value:request_id"},
+			{Name: "deadline", Disabled: true},
+		}})
+		require.NoError(t, err)
+
+		return cl.With(WithFieldsExtractor())
+	}
+
+	serve := func(t *testing.T, h http.Handler, method, body string) (int, map[string]any) {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/extractors", strings.NewReader(body)))
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+		return rec.Code, resp
+	}
+
+	t.Run("lists extractors with statistics", func(t *testing.T) {
+		cl := newLogger(t)
+		cl.Info(ctx, "one")
+		cl.Info(ctx, "two")
+
+		code, resp := serve(t, NewAdminHandler(cl), http.MethodGet, "")
+		require.Equal(t, http.StatusOK, code)
+
+		extractors, ok := resp["extractors"].([]any)
+		require.True(t, ok)
+		require.Len(t, extractors, 3)
+
+		first := extractors[0].(map[string]any)
+		require.Equal(t, "value:request_id", first["name"])
+		require.Equal(t, true, first["enabled"])
+		require.Equal(t, float64(2), first["calls"])
+		require.Equal(t, true, first["reconfigurable"])
+		require.Contains(t, first, "avgCostNs")
+
+		second := extractors[1].(map[string]any)
+		require.Equal(t, "deadline", second["name"])
+		require.Equal(t, false, second["enabled"])
+		require.Equal(t, float64(0), second["calls"])
+
+		third := extractors[2].(map[string]any)
+		require.Equal(t, "context-logger.contextFields", third["name"])
+		require.Equal(t, false, third["reconfigurable"])
+	})
+
+	t.Run("toggles extractors", func(t *testing.T) {
+		cl := newLogger(t)
+		h := NewAdminHandler(cl)
+
+		code, _ := serve(t, h, http.MethodPut, `{"name":"deadline","enabled":true}`)
+		require.Equal(t, http.StatusOK, code)
+		require.True(t, cl.ExtractorStats()[1].Enabled)
+
+		code, _ = serve(t, h, http.MethodPut, `{"name":"value:request_id","enabled":false}`)
+		require.Equal(t, http.StatusOK, code)
+		require.False(t, cl.ExtractorStats()[0].Enabled)
+	})
+
+	t.Run("rebuilds extractors with new options", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl, err := NewFromConfig(logger, Config{Extractors: []ExtractorConfig{{Name: "value:request_id"}}})
+		require.NoError(t, err)
+
+		code, resp := serve(t, NewAdminHandler(cl), http.MethodPut, `{"name":"value:request_id","options":{"default":"none"}}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]any{"default": "none"}, resp["extractors"].([]any)[0].(map[string]any)["options"])
+
+		require.Equal(t, "none", logAndAssert(t, context.Background(), observed, cl, "default")["request_id"])
+	})
+
+	t.Run("reports errors", func(t *testing.T) {
+		h := NewAdminHandler(newLogger(t))
+
+		tests := []struct {
+			method, body string
+			code         int
+		}{
+			{http.MethodPut, `not json`, http.StatusBadRequest},
+			{http.MethodPut, `{"enabled":true}`, http.StatusBadRequest},
+			{http.MethodPut, `{"name":"deadline"}`, http.StatusBadRequest},
+			{http.MethodPut, `{"name":"missing","enabled":true}`, http.StatusNotFound},
+			{http.MethodPut, `{"name":"missing","options":{}}`, http.StatusNotFound},
+			{http.MethodPut, `{"name":"context-logger.contextFields","options":{}}`, http.StatusBadRequest},
+			{http.MethodPost, `{}`, http.StatusMethodNotAllowed},
+		}
+
+		for _, tt := range tests {
+			code, resp := serve(t, h, tt.method, tt.body)
+			require.Equal(t, tt.code, code, tt.body)
+			require.NotEmpty(t, resp["error"], tt.body)
+		}
+	})
+
+	t.Run("handles nil logger", func(t *testing.T) {
+		code, resp := serve(t, NewAdminHandler(nil), http.MethodGet, "")
+
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []any{}, resp["extractors"])
+	})
+}
+
+func TestContextLogger_ReconfigureExtractor(t *testing.T) {
+	t.Run("keeps enabled state and restarts statistics", func(t *testing.T) {
+		logger, _ := newTestLogger()
+		cl, err := NewFromConfig(logger, Config{Extractors: []ExtractorConfig{{Name: "test_static:a", Disabled: true}}})
+		require.NoError(t, err)
+
+		cl.SetEnabled("test_static:a", true)
+		cl.Info(context.Background(), "counted")
+		require.Equal(t, uint64(1), cl.ExtractorStats()[0].Calls)
+
+		require.NoError(t, cl.With(WithDeadlineExtractor()).ReconfigureExtractor("test_static:a", map[string]string{"suffix": "b"}))
+
+		stats := cl.ExtractorStats()[0]
+		require.True(t, stats.Enabled)
+		require.Zero(t, stats.Calls)
+		require.Equal(t, map[string]string{"suffix": "b"}, stats.Options)
+		require.Len(t, cl.run(context.Background(), 0), 2)
+	})
+
+	t.Run("rejects invalid options without changes", func(t *testing.T) {
+		cl, err := NewFromConfig(nil, Config{Extractors: []ExtractorConfig{
+			{Name: "test_static:a", Options: map[string]string{"suffix": "a"}},
+		}})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, cl.ReconfigureExtractor("missing", nil), ErrExtractorNotFound)
+		require.ErrorContains(t, cl.ReconfigureExtractor("test_static:a", map[string]string{"fail": "bad option"}), "bad option")
+		require.Equal(t, map[string]string{"suffix": "a"}, cl.ExtractorStats()[0].Options)
+	})
+}
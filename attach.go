@@ -0,0 +1,62 @@
+package contextlogger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type attachedContextKey struct{}
+
+// attachment holds the fields extracted by owner when Attach or Snapshot was
+// called.
+type attachment struct {
+	owner  *ContextLogger
+	fields []zap.Field
+}
+
+// Attach runs the extractors against ctx once and returns a copy of ctx
+// carrying the result. Logging through c with the returned context or its
+// children reuses these fields instead of running the extractors again, so
+// values that change later, such as bag fields or the time left before the
+// deadline, are logged as they were at Attach. Other ContextLoggers, including
+// ones derived with With, ignore the attached fields. A nil context is
+// treated as context.Background().
+func (c *ContextLogger) Attach(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, attachedContextKey{}, c.attach(ctx))
+}
+
+// attach returns the fields c attached to ctx, running the extractors only if
+// there are none yet.
+func (c *ContextLogger) attach(ctx context.Context) *attachment {
+	if a, ok := attachmentOf(ctx, c); ok {
+		return a
+	}
+
+	return &attachment{owner: c, fields: c.run(ctx, 0)}
+}
+
+// attachedFields returns the fields attached to ctx by owner. The result is
+// shared and must not be modified.
+func attachedFields(ctx context.Context, owner *ContextLogger) ([]zap.Field, bool) {
+	a, ok := attachmentOf(ctx, owner)
+	if !ok {
+		return nil, false
+	}
+
+	return a.fields, true
+}
+
+// attachmentOf returns the attachment of owner carried by ctx.
+func attachmentOf(ctx context.Context, owner *ContextLogger) (*attachment, bool) {
+	a, ok := ctx.Value(attachedContextKey{}).(*attachment)
+	if !ok || a.owner != owner {
+		return nil, false
+	}
+
+	return a, true
+}
@@ -0,0 +1,73 @@
+package contextlogger
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContextLogger_Attach(t *testing.T) {
+	key := contextKeyString("request_id")
+	base := context.WithValue(context.Background(), key, "req-1")
+
+	t.Run("reuses attached fields instead of running extractors", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		var calls atomic.Int32
+		cl := New(logger, countingExtractor(&calls), WithValueExtractor(key))
+
+		ctx := cl.Attach(base)
+		require.Equal(t, int32(1), calls.Load())
+
+		child := context.WithValue(ctx, contextKeyString("other"), "value")
+		for range 3 {
+			cl.Ctx(child).Info("attached")
+		}
+		cl.Info(ctx, "level-method", zap.Int("status", 200))
+
+		require.Equal(t, int32(1), calls.Load())
+		entries := observed.TakeAll()
+		require.Len(t, entries, 4)
+		for _, entry := range entries {
+			require.Equal(t, "req-1", entry.ContextMap()[key.String()])
+			require.Equal(t, "yes", entry.ContextMap()["counted"])
+		}
+		require.Equal(t, int64(200), entries[3].ContextMap()["status"])
+	})
+
+	t.Run("freezes values at attach time", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithBagExtractor())
+
+		ctx := NewBag(context.Background())
+		Set(ctx, zap.String("user_id", "anonymous"))
+		ctx = cl.Attach(ctx)
+		Set(ctx, zap.String("user_id", "u-42"))
+
+		fields := logAndAssert(t, ctx, observed, cl, "frozen")
+		require.Equal(t, "anonymous", fields["user_id"])
+	})
+
+	t.Run("other loggers run their extractors", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		var calls atomic.Int32
+		cl := New(logger, WithValueExtractor(key))
+		child := cl.With(countingExtractor(&calls))
+
+		ctx := cl.Attach(base)
+		fields := logAndAssert(t, ctx, observed, child, "child")
+
+		require.Equal(t, int32(1), calls.Load())
+		require.Equal(t, "req-1", fields[key.String()])
+	})
+
+	t.Run("handles nil context", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		ctx := cl.Attach(nil)
+		logAndAssert(t, ctx, observed, cl, "nil-context")
+	})
+}
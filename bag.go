@@ -0,0 +1,83 @@
+package contextlogger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+type bagContextKey struct{}
+
+// bag holds fields shared by every context derived from the one that carries
+// it. Writers copy the field slice, so readers never lock.
+type bag struct {
+	mu     sync.Mutex
+	fields atomic.Pointer[[]zap.Field]
+}
+
+// NewBag returns a copy of ctx carrying an empty, goroutine-safe field bag.
+// Fields added with Set on any context derived from the result are visible to
+// every logger extracting from such a context, including loggers in callers
+// that created the bag. A nil context is treated as context.Background().
+func NewBag(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, bagContextKey{}, &bag{})
+}
+
+// Set adds fields to the nearest bag in ctx, replacing bag fields with the same
+// key, and reports whether ctx carries a bag.
+func Set(ctx context.Context, fields ...zap.Field) bool {
+	if ctx == nil {
+		return false
+	}
+
+	b, ok := ctx.Value(bagContextKey{}).(*bag)
+	if !ok {
+		return false
+	}
+
+	if len(fields) == 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var current []zap.Field
+	if loaded := b.fields.Load(); loaded != nil {
+		current = *loaded
+	}
+
+	updated := make([]zap.Field, len(current), len(current)+len(fields))
+	copy(updated, current)
+
+	for _, f := range fields {
+		updated = setField(updated, f)
+	}
+
+	b.fields.Store(&updated)
+
+	return true
+}
+
+// WithBagExtractor adds the fields set in the nearest bag of the context, in
+// the order their keys were first set.
+func WithBagExtractor() ContextExtractor {
+	return func(ctx context.Context) []zap.Field {
+		b, ok := ctx.Value(bagContextKey{}).(*bag)
+		if !ok {
+			return nil
+		}
+
+		if fields := b.fields.Load(); fields != nil {
+			return *fields
+		}
+
+		return nil
+	}
+}
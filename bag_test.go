@@ -0,0 +1,83 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBag(t *testing.T) {
+	logger, observed := newTestLogger()
+	cl := New(logger, WithBagExtractor())
+
+	t.Run("fields set downstream are visible upstream", func(t *testing.T) {
+		observed.TakeAll()
+		ctx := NewBag(context.Background())
+
+		authenticate := func(ctx context.Context) {
+			ctx = context.WithValue(ctx, contextKeyString("unrelated"), "value")
+			require.True(t, Set(ctx, zap.String("user_id", "u-42")))
+		}
+		authenticate(ctx)
+
+		fields := logAndAssert(t, ctx, observed, cl, "access-log")
+		require.Equal(t, "u-42", fields["user_id"])
+	})
+
+	t.Run("later values replace earlier ones by key", func(t *testing.T) {
+		observed.TakeAll()
+		ctx := NewBag(context.Background())
+		Set(ctx, zap.String("user_id", "anonymous"), zap.String("tenant_id", "t-1"))
+		Set(ctx, zap.String("user_id", "u-42"))
+
+		cl.Ctx(ctx).Info("replaced")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "u-42", entries[0].ContextMap()["user_id"])
+		require.Equal(t, "user_id", entries[0].Context[1].Key)
+		require.Equal(t, "tenant_id", entries[0].Context[2].Key)
+	})
+
+	t.Run("extracted fields are not affected by later sets", func(t *testing.T) {
+		ctx := NewBag(context.Background())
+		Set(ctx, zap.String("user_id", "anonymous"))
+
+		extracted := WithBagExtractor()(ctx)
+		Set(ctx, zap.String("user_id", "u-42"))
+
+		require.Equal(t, "anonymous", extracted[0].String)
+	})
+
+	t.Run("set without bag reports false", func(t *testing.T) {
+		require.False(t, Set(context.Background(), zap.String("user_id", "u-1")))
+		require.False(t, Set(nil, zap.String("user_id", "u-1")))
+		require.True(t, Set(NewBag(nil)))
+		require.Empty(t, WithBagExtractor()(context.Background()))
+		require.Empty(t, WithBagExtractor()(NewBag(context.Background())))
+	})
+
+	t.Run("concurrent sets and extraction", func(t *testing.T) {
+		ctx := NewBag(context.Background())
+		extract := WithBagExtractor()
+
+		var wg sync.WaitGroup
+		for i := range 16 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 100 {
+					Set(ctx, zap.Int(fmt.Sprintf("key_%d", i), j))
+					_ = extract(ctx)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, extract(ctx), 16)
+	})
+}
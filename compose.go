@@ -0,0 +1,155 @@
+package contextlogger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Namespace groups the fields of extractors under a single nested object
+// named name, such as ctx.trace_id, so they cannot collide with call-site
+// fields or fields from other libraries. Carrier fields stay at the top level
+// for custom cores, as do reports of panicking extractors. Nothing is added
+// when the extractors return no fields; an empty name adds the fields
+// ungrouped.
+func Namespace(name string, extractors ...ContextExtractor) ContextExtractor {
+	extractors = append([]ContextExtractor(nil), extractors...)
+
+	return func(ctx context.Context) []zap.Field {
+		var fields []zap.Field
+
+		for _, f := range extractors {
+			if f == nil {
+				continue
+			}
+
+			extracted, _ := callExtractor(ctx, "", f)
+			fields = append(fields, extracted...)
+		}
+
+		return nestFields(name, fields)
+	}
+}
+
+// nestFields nests fields under name, keeping carrier fields and reports of
+// panicking extractors at the top level.
+func nestFields(name string, fields []zap.Field) []zap.Field {
+	if name == "" {
+		return fields
+	}
+
+	var nested, topLevel []zap.Field
+
+	for _, field := range fields {
+		if _, ok := field.Interface.(extractorPanic); ok || field.Type == zapcore.SkipType {
+			topLevel = append(topLevel, field)
+		} else {
+			nested = append(nested, field)
+		}
+	}
+
+	if len(nested) == 0 {
+		return topLevel
+	}
+
+	return append(topLevel, zap.Object(name, fieldsObject(nested)))
+}
+
+// When runs e only when pred reports true for the context, such as for
+// sampled requests or a feature flag. A nil pred always runs e.
+func When(pred func(ctx context.Context) bool, e ContextExtractor) ContextExtractor {
+	return func(ctx context.Context) []zap.Field {
+		if e == nil || (pred != nil && !pred(ctx)) {
+			return nil
+		}
+
+		fields, _ := callExtractor(ctx, "", e)
+
+		return fields
+	}
+}
+
+// FirstOf returns the fields of the first extractor that returns any, so
+// alternative sources of the same fields can be chained: OpenTelemetry trace
+// IDs, else Sentry, else a generated ID. A panicking extractor is reported and
+// the next one is tried.
+func FirstOf(extractors ...ContextExtractor) ContextExtractor {
+	extractors = append([]ContextExtractor(nil), extractors...)
+
+	return func(ctx context.Context) []zap.Field {
+		var reports []zap.Field
+
+		for _, f := range extractors {
+			if f == nil {
+				continue
+			}
+
+			fields, panicked := callExtractor(ctx, "", f)
+			if panicked {
+				reports = append(reports, fields...)
+				continue
+			}
+
+			if len(fields) > 0 {
+				return append(reports, fields...)
+			}
+		}
+
+		return reports
+	}
+}
+
+// Merge runs every extractor and keeps only the first field for each key, so
+// extractors that overlap, such as two tracing integrations during a
+// migration, log each key once. Carrier fields are always kept.
+func Merge(extractors ...ContextExtractor) ContextExtractor {
+	extractors = append([]ContextExtractor(nil), extractors...)
+
+	return func(ctx context.Context) []zap.Field {
+		var (
+			merged []zap.Field
+			seen   map[string]struct{}
+		)
+
+		for _, f := range extractors {
+			if f == nil {
+				continue
+			}
+
+			fields, _ := callExtractor(ctx, "", f)
+
+			for _, field := range fields {
+				if !dedupable(field) {
+					merged = append(merged, field)
+					continue
+				}
+
+				if _, ok := seen[field.Key]; ok {
+					continue
+				}
+
+				if seen == nil {
+					seen = make(map[string]struct{}, len(fields))
+				}
+
+				seen[field.Key] = struct{}{}
+				merged = append(merged, field)
+			}
+		}
+
+		return merged
+	}
+}
+
+// fieldsObject marshals fields as the members of a nested object.
+type fieldsObject []zap.Field
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (f fieldsObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for i := range f {
+		f[i].AddTo(enc)
+	}
+
+	return nil
+}
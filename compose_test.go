@@ -0,0 +1,131 @@
+package contextlogger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNamespace(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+	ctx = WithFields(ctx, zap.String("trace_id", "abc"))
+
+	t.Run("groups fields under a nested object", func(t *testing.T) {
+		var buf bytes.Buffer
+		encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+		logger := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(&buf), zap.InfoLevel))
+		cl := New(logger, Namespace("ctx", WithValueExtractor(key), nil, WithFieldsExtractor()))
+
+		cl.Info(ctx, "grouped", zap.String("trace_id", "call-site"))
+
+		require.JSONEq(t,
+			`{"msg":"grouped","ctx":{"request_id":"req-1","trace_id":"abc"},"trace_id":"call-site"}`,
+			buf.String(),
+		)
+	})
+
+	t.Run("keeps carrier fields at the top level", func(t *testing.T) {
+		fields := Namespace("ctx", WithContextCarrier("carrier"), WithValueExtractor(key))(ctx)
+
+		require.Len(t, fields, 2)
+		require.Equal(t, "carrier", fields[0].Key)
+		require.Equal(t, zapcore.SkipType, fields[0].Type)
+		require.Equal(t, "ctx", fields[1].Key)
+	})
+
+	t.Run("adds nothing without fields", func(t *testing.T) {
+		require.Empty(t, Namespace("ctx", WithValueExtractor(key))(context.Background()))
+
+		carrierOnly := Namespace("ctx", WithContextCarrier("carrier"))(context.Background())
+		require.Len(t, carrierOnly, 1)
+		require.Equal(t, "carrier", carrierOnly[0].Key)
+	})
+
+	t.Run("empty name flattens fields", func(t *testing.T) {
+		fields := Namespace("", WithValueExtractor(key), WithFieldsExtractor())(ctx)
+
+		require.Equal(t, []zap.Field{zap.Any("request_id", "req-1"), zap.String("trace_id", "abc")}, fields)
+	})
+}
+
+func TestWhen(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+	sampled := func(ctx context.Context) bool { return ctx.Value(contextKeyString("sampled")) != nil }
+
+	extractor := When(sampled, WithValueExtractor(key))
+
+	require.Empty(t, extractor(ctx))
+	require.Equal(t,
+		[]zap.Field{zap.Any("request_id", "req-1")},
+		extractor(context.WithValue(ctx, contextKeyString("sampled"), true)),
+	)
+	require.Equal(t, []zap.Field{zap.Any("request_id", "req-1")}, When(nil, WithValueExtractor(key))(ctx))
+	require.Empty(t, When(sampled, nil)(ctx))
+}
+
+func TestFirstOf(t *testing.T) {
+	otelKey := contextKeyString("otel")
+	sentryKey := contextKeyString("sentry")
+	generated := func(context.Context) []zap.Field {
+		return []zap.Field{zap.String("trace_id", "generated")}
+	}
+	extractor := FirstOf(nil, WithValueExtractor(otelKey), WithValueExtractor(sentryKey), generated)
+
+	t.Run("uses the first extractor with fields", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), otelKey, "o")
+		ctx = context.WithValue(ctx, sentryKey, "s")
+
+		require.Equal(t, []zap.Field{zap.Any("otel", "o")}, extractor(ctx))
+	})
+
+	t.Run("falls back in order", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), sentryKey, "s")
+
+		require.Equal(t, []zap.Field{zap.Any("sentry", "s")}, extractor(ctx))
+		require.Equal(t, []zap.Field{zap.String("trace_id", "generated")}, extractor(context.Background()))
+	})
+
+	t.Run("reports panics and tries the next extractor", func(t *testing.T) {
+		fields := FirstOf(panickingExtractor, generated)(context.Background())
+
+		require.Len(t, fields, 2)
+		require.Equal(t, FieldContextExtractorError, fields[0].Key)
+		require.Equal(t, "trace_id", fields[1].Key)
+	})
+
+	t.Run("returns nothing when no extractor has fields", func(t *testing.T) {
+		require.Empty(t, FirstOf(WithValueExtractor(otelKey))(context.Background()))
+		require.Empty(t, FirstOf()(context.Background()))
+	})
+}
+
+func TestMerge(t *testing.T) {
+	otel := func(context.Context) []zap.Field {
+		return []zap.Field{zap.String("trace_id", "otel"), zap.String("span_id", "o-span")}
+	}
+	sentry := func(context.Context) []zap.Field {
+		return []zap.Field{
+			zap.String("trace_id", "sentry"),
+			zap.String("span_id", "s-span"),
+			zap.String("span_op", "http.server"),
+		}
+	}
+
+	fields := Merge(otel, nil, sentry, WithContextCarrier("carrier"), WithContextCarrier("carrier"))(context.Background())
+
+	require.Len(t, fields, 5)
+	require.Equal(t, []zap.Field{
+		zap.String("trace_id", "otel"),
+		zap.String("span_id", "o-span"),
+		zap.String("span_op", "http.server"),
+	}, fields[:3])
+	require.Equal(t, zapcore.SkipType, fields[3].Type)
+	require.Equal(t, zapcore.SkipType, fields[4].Type)
+	require.Empty(t, Merge()(context.Background()))
+}
@@ -0,0 +1,94 @@
+package contextlogger
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Config describes a ContextLogger in a form that decodes from JSON or YAML,
+// so platform configuration rather than each service's code decides which
+// context fields are logged.
+type Config struct {
+	// Extractors lists the registered extractors to run, in order.
+	Extractors []ExtractorConfig `json:"extractors" yaml:"extractors"`
+	// Namespace groups the extracted fields under a nested object; see
+	// GroupFields.
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// FieldNames selects a field name preset: ecs, otel, or datadog.
+	FieldNames string `json:"fieldNames" yaml:"fieldNames"`
+	// Rename renames extracted fields, after the preset; see RenameFields.
+	Rename map[string]string `json:"rename" yaml:"rename"`
+	// Duplicates sets the duplicate key policy; see DuplicateKeys.
+	Duplicates DuplicatePolicy `json:"duplicates" yaml:"duplicates"`
+	// Limits caps the size of extracted fields; see LimitFields.
+	Limits Limits `json:"limits" yaml:"limits"`
+}
+
+// ExtractorConfig selects a registered extractor.
+type ExtractorConfig struct {
+	// Name is a registered name optionally followed by a colon and a
+	// parameter, such as "deadline", "otel", or "value:request_id".
+	Name string `json:"name" yaml:"name"`
+	// Options are passed to the extractor factory.
+	Options map[string]string `json:"options" yaml:"options"`
+	// Disabled adds the extractor switched off, to be enabled at runtime by
+	// its Name; see ContextLogger.SetEnabled.
+	Disabled bool `json:"disabled" yaml:"disabled"`
+}
+
+// NewFromConfig creates a ContextLogger running the extractors cfg selects,
+// named by their ExtractorConfig.Name, with the options it describes. Like
+// New, it falls back to a no-op logger when logger is nil. Extractors other
+// than the built-in ones must be registered before it is called, with
+// RegisterExtractor or by importing a package that registers them, such as
+// otel-extractor.
+func NewFromConfig(logger *zap.Logger, cfg Config) (*ContextLogger, error) {
+	extractors := make([]NamedExtractor, 0, len(cfg.Extractors))
+
+	var errs []error
+
+	for _, ec := range cfg.Extractors {
+		extractor, err := NewNamedExtractor(ec.Name, ec.Options)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		extractor.Disabled = ec.Disabled
+		extractors = append(extractors, extractor)
+	}
+
+	opts := []Option{DuplicateKeys(cfg.Duplicates), LimitFields(cfg.Limits)}
+
+	if cfg.FieldNames != "" {
+		preset, err := fieldNamePreset(cfg.FieldNames)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		opts = append(opts, preset)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, RenameFields(cfg.Rename), GroupFields(cfg.Namespace))
+
+	return New(logger).WithNamed(extractors...).WithOptions(opts...), nil
+}
+
+func fieldNamePreset(name string) (Option, error) {
+	switch name {
+	case "ecs":
+		return ECSFieldNames(), nil
+	case "otel":
+		return OTelFieldNames(), nil
+	case "datadog":
+		return DatadogFieldNames(), nil
+	default:
+		return nil, fmt.Errorf("unknown field name preset %q", name)
+	}
+}
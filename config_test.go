@@ -0,0 +1,82 @@
+package contextlogger
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	ctx := context.WithValue(context.Background(), registryTestKey, "req-1")
+	ctx = WithFields(ctx, zap.String("trace_id", "abc"), zap.String("tenant", "acme-corp"))
+
+	t.Run("decodes and applies json config", func(t *testing.T) {
+		var cfg Config
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"extractors": [
+				{"name": "value:request_id"},
+				{"name": "fields"},
+				{"name": "test_static:from-config"}
+			],
+			"namespace": "ctx",
+			"fieldNames": "ecs",
+			"rename": {"tenant": "tenant.id"},
+			"duplicates": "first",
+			"limits": {"maxStringBytes": 5}
+		}`), &cfg))
+
+		logger, observed := newTestLogger()
+		cl, err := NewFromConfig(logger, cfg)
+		require.NoError(t, err)
+
+		fields := logAndAssert(t, ctx, observed, cl, "configured")
+		require.Equal(t, map[string]interface{}{
+			"request_id": "req-1",
+			"trace.id":   "abc",
+			"tenant.id":  "acme-",
+			"static":     "from-",
+		}, fields["ctx"])
+		require.Equal(t, []interface{}{"ctx.tenant.id", "ctx.static"}, fields[FieldContextTruncated])
+		require.Equal(t, FirstWins, cl.opts.duplicates)
+	})
+
+	t.Run("empty config logs without extractors", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl, err := NewFromConfig(logger, Config{Namespace: "ctx"})
+		require.NoError(t, err)
+
+		fields := logAndAssert(t, ctx, observed, cl, "empty")
+		require.Equal(t, map[string]interface{}{"text": "test"}, fields)
+	})
+
+	t.Run("names extractors after their config entries", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl, err := NewFromConfig(logger, Config{
+			Extractors: []ExtractorConfig{{Name: "value:request_id", Disabled: true}},
+		})
+		require.NoError(t, err)
+		require.NotContains(t, logAndAssert(t, ctx, observed, cl, "disabled"), "request_id")
+
+		require.True(t, cl.SetEnabled("value:request_id", true))
+		require.Equal(t, "req-1", logAndAssert(t, ctx, observed, cl, "enabled")["request_id"])
+	})
+
+	t.Run("reports every invalid entry", func(t *testing.T) {
+		_, err := NewFromConfig(nil, Config{
+			Extractors: []ExtractorConfig{{Name: "unknown"}, {Name: "deadline"}, {Name: "value:missing"}},
+			FieldNames: "splunk",
+		})
+
+		require.ErrorContains(t, err, `unknown extractor "unknown"`)
+		require.ErrorContains(t, err, `no context key registered as "missing"`)
+		require.ErrorContains(t, err, `unknown field name preset "splunk"`)
+	})
+
+	t.Run("rejects unknown duplicate policies", func(t *testing.T) {
+		var cfg Config
+		require.Error(t, json.Unmarshal([]byte(`{"duplicates": "newest"}`), &cfg))
+	})
+}
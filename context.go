@@ -0,0 +1,58 @@
+package contextlogger
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+type loggerContextKey struct{}
+
+var (
+	nopLogger     = New(nil)
+	defaultLogger atomic.Pointer[ContextLogger]
+)
+
+// ToContext returns a copy of ctx carrying logger for FromContext. A nil
+// logger is stored as a no-op ContextLogger, as New does for a nil
+// *zap.Logger. A nil context is treated as context.Background().
+func ToContext(ctx context.Context, logger *ContextLogger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if logger == nil {
+		logger = nopLogger
+	}
+
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by ToContext bound to ctx, see
+// ContextLogger.Ctx. Without a stored logger it uses the default set by
+// SetDefault, or a no-op logger.
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerContextKey{}).(*ContextLogger); ok {
+			return logger.Ctx(ctx)
+		}
+	}
+
+	return Default().Ctx(ctx)
+}
+
+// SetDefault sets the logger FromContext falls back to. A nil logger restores
+// the no-op default.
+func SetDefault(logger *ContextLogger) {
+	defaultLogger.Store(logger)
+}
+
+// Default returns the logger FromContext falls back to.
+func Default() *ContextLogger {
+	if logger := defaultLogger.Load(); logger != nil {
+		return logger
+	}
+
+	return nopLogger
+}
@@ -0,0 +1,81 @@
+package contextlogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToContext(t *testing.T) {
+	key := contextKeyString("request_id")
+
+	t.Run("stored logger applies extractors", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		ctx := ToContext(context.Background(), cl)
+		ctx = context.WithValue(ctx, key, "req-1")
+		FromContext(ctx).Info("from-context")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+	})
+
+	t.Run("nil logger is stored as no-op", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		SetDefault(New(logger))
+		t.Cleanup(func() { SetDefault(nil) })
+
+		ctx := ToContext(context.Background(), nil)
+		FromContext(ctx).Info("dropped")
+
+		require.Empty(t, observed.TakeAll())
+	})
+
+	t.Run("nil context is treated as background", func(t *testing.T) {
+		logger, observed := newTestLogger()
+
+		//nolint:staticcheck // a nil context must not panic
+		ctx := ToContext(nil, New(logger))
+		FromContext(ctx).Info("nil-context")
+
+		require.Len(t, observed.TakeAll(), 1)
+	})
+}
+
+func TestFromContext_Default(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+
+	t.Run("falls back to no-op logger", func(t *testing.T) {
+		require.Same(t, nopLogger, Default())
+		require.NotPanics(t, func() {
+			FromContext(ctx).Info("nop")
+			FromContext(nil).Info("nop")
+		})
+	})
+
+	t.Run("falls back to configured default", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+		SetDefault(cl)
+		t.Cleanup(func() { SetDefault(nil) })
+
+		require.Same(t, cl, Default())
+		FromContext(ctx).Info("default")
+		FromContext(nil).Info("default-nil")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 2)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+	})
+
+	t.Run("nil restores no-op default", func(t *testing.T) {
+		SetDefault(New(nil))
+		SetDefault(nil)
+
+		require.Same(t, nopLogger, Default())
+	})
+}
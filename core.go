@@ -0,0 +1,110 @@
+package contextlogger
+
+import (
+	"context"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// contextCore adds fields extracted from a context to the entries its wrapped
+// core agrees to write. Extraction is deferred until Write, so entries dropped
+// by level checks or sampling never run extractors.
+type contextCore struct {
+	zapcore.Core
+	logger *ContextLogger
+	ctx    context.Context
+}
+
+// NewCore wraps core so that extractors run at write time against the context
+// carried by the entry. The context comes from a carrier field (see
+// ContextField and WithContextCarrier) added with With or at the call site;
+// entries without one are written unchanged.
+func NewCore(core zapcore.Core, extractors ...ContextExtractor) zapcore.Core {
+	return &contextCore{Core: core, logger: New(nil, extractors...)}
+}
+
+// With adds fields to the wrapped core and remembers any carried context.
+func (c *contextCore) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+
+	clone := *c
+	if ctx, ok := carriedContext(fields); ok {
+		clone.ctx = ctx
+	}
+
+	clone.Core = c.Core.With(fields)
+
+	return &clone
+}
+
+// Check delegates to the wrapped core and defers extraction to Write. The
+// entry the wrapped core accepts keeps its own decisions, such as which of
+// several teed cores and samplers take the entry.
+func (c *contextCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked := c.Core.Check(ent, nil)
+	if checked == nil {
+		return ce
+	}
+
+	written := &checkedCore{contextCore: c, checked: checked}
+	ce = ce.AddCore(ent, written)
+	written.outer = ce
+
+	return ce
+}
+
+// Write extracts fields and writes the entry to the wrapped core.
+func (c *contextCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.fields(fields))
+}
+
+// checkedCore writes an entry that the wrapped core has already accepted,
+// preserving the wrapped core's own Check decisions such as sampling.
+type checkedCore struct {
+	*contextCore
+	checked *zapcore.CheckedEntry
+	outer   *zapcore.CheckedEntry
+}
+
+// Write extracts fields and writes them through the accepted entry. Its
+// cores' write errors are reported to the error output of the entry being
+// written, as zap reports them, instead of being returned and reported twice.
+func (c *checkedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.checked.Entry = ent
+	c.checked.ErrorOutput = c.outer.ErrorOutput
+	c.checked.Write(c.fields(fields)...)
+
+	return nil
+}
+
+// fields prepends the extracted fields to fields. A context carried at the
+// call site takes precedence over the one bound to the core.
+func (c *contextCore) fields(fields []zapcore.Field) []zapcore.Field {
+	ctx := c.ctx
+	if carried, ok := carriedContext(fields); ok {
+		ctx = carried
+	}
+
+	if ctx == nil {
+		return fields
+	}
+
+	return c.logger.extract(ctx, fields)
+}
+
+// carriedContext returns the context of the last carrier field in fields.
+func carriedContext(fields []zapcore.Field) (context.Context, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Type != zapcore.SkipType {
+			continue
+		}
+
+		if ctx, ok := fields[i].Interface.(context.Context); ok {
+			return ctx, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,179 @@
+package contextlogger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func countingExtractor(calls *atomic.Int32) ContextExtractor {
+	return func(context.Context) []zap.Field {
+		calls.Add(1)
+		return []zap.Field{zap.String("counted", "yes")}
+	}
+}
+
+var errDiskFull = errors.New("disk full")
+
+// failingCore accepts every entry and fails to write it.
+type failingCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c failingCore) With([]zapcore.Field) zapcore.Core { return c }
+
+func (c failingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (failingCore) Write(zapcore.Entry, []zapcore.Field) error { return errDiskFull }
+
+func (failingCore) Sync() error { return nil }
+
+func TestContextLogger_LazyExtraction(t *testing.T) {
+	logger, observed := newTestLogger()
+
+	t.Run("skips extractors for disabled levels", func(t *testing.T) {
+		observed.TakeAll()
+		var calls atomic.Int32
+		cl := New(logger, countingExtractor(&calls))
+
+		cl.Ctx(context.Background()).Debug("dropped")
+
+		require.Zero(t, calls.Load())
+		require.Empty(t, observed.TakeAll())
+	})
+
+	t.Run("runs extractors once per written entry", func(t *testing.T) {
+		observed.TakeAll()
+		var calls atomic.Int32
+		cl := New(logger, countingExtractor(&calls))
+
+		l := cl.Ctx(context.Background())
+		l.Info("first")
+		l.Info("second")
+
+		require.Equal(t, int32(2), calls.Load())
+		entries := observed.TakeAll()
+		require.Len(t, entries, 2)
+		require.Equal(t, "yes", entries[1].ContextMap()["counted"])
+	})
+
+	t.Run("respects sampling of the wrapped core", func(t *testing.T) {
+		core, sampledObserved := observer.New(zap.InfoLevel)
+		sampled := zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0)
+		var calls atomic.Int32
+		cl := New(zap.New(sampled), countingExtractor(&calls))
+
+		for range 5 {
+			cl.Ctx(context.Background()).Info("sampled")
+		}
+
+		require.Equal(t, int32(1), calls.Load())
+		require.Len(t, sampledObserved.TakeAll(), 1)
+	})
+
+	t.Run("keeps fields added with With", func(t *testing.T) {
+		observed.TakeAll()
+		key := contextKeyString("request_id")
+		cl := New(logger, WithValueExtractor(key))
+		ctx := context.WithValue(context.Background(), key, "req-1")
+
+		cl.Ctx(ctx).With(zap.String("component", "db")).Info("with-fields")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "req-1", fields[key.String()])
+		require.Equal(t, "db", fields["component"])
+		require.Equal(t, "test", fields["text"])
+	})
+
+	t.Run("reports write errors of the wrapped core", func(t *testing.T) {
+		var errOut bytes.Buffer
+		failing := zap.New(failingCore{zap.InfoLevel}, zap.ErrorOutput(zapcore.AddSync(&errOut)))
+		cl := New(failing, countingExtractor(new(atomic.Int32)))
+
+		cl.Ctx(context.Background()).Info("lost")
+
+		require.Equal(t, 1, strings.Count(errOut.String(), "write error: disk full"), errOut.String())
+	})
+
+	t.Run("writes only to the teed cores that accept the entry", func(t *testing.T) {
+		infoCore, infoObserved := observer.New(zap.InfoLevel)
+		debugCore, debugObserved := observer.New(zap.DebugLevel)
+		cl := New(zap.New(zapcore.NewTee(infoCore, debugCore)), countingExtractor(new(atomic.Int32)))
+
+		cl.Ctx(context.Background()).Debug("detail")
+
+		require.Zero(t, infoObserved.Len())
+		require.Equal(t, 1, debugObserved.Len())
+	})
+
+	t.Run("returns underlying logger without extractors", func(t *testing.T) {
+		cl := New(logger)
+
+		require.Same(t, logger, cl.Ctx(context.Background()))
+	})
+}
+
+func TestNewCore(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-42")
+
+	t.Run("extracts from call site carrier", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		logger := zap.New(NewCore(core, WithValueExtractor(key)))
+
+		logger.Info("call-site", ContextField("ctx", ctx))
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-42", entries[0].ContextMap()[key.String()])
+	})
+
+	t.Run("extracts from carrier added with With", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		logger := zap.New(NewCore(core, WithValueExtractor(key))).With(ContextField("ctx", ctx))
+
+		logger.Info("with-carrier")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-42", entries[0].ContextMap()[key.String()])
+	})
+
+	t.Run("writes entries without carrier unchanged", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		var calls atomic.Int32
+		logger := zap.New(NewCore(core, countingExtractor(&calls)))
+
+		logger.Info("no-carrier", zap.String("k", "v"))
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Zero(t, calls.Load())
+		require.Equal(t, map[string]interface{}{"k": "v"}, entries[0].ContextMap())
+	})
+
+	t.Run("extracts on direct Write", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		wrapped := NewCore(core, WithValueExtractor(key))
+
+		err := wrapped.Write(zapcore.Entry{Message: "direct"}, []zapcore.Field{ContextField("ctx", ctx)})
+
+		require.NoError(t, err)
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-42", entries[0].ContextMap()[key.String()])
+	})
+}
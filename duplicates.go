@@ -0,0 +1,161 @@
+package contextlogger
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DuplicatePolicy decides which field is logged when extracted fields and
+// call-site fields share a key. Zap writes every field, so without a policy
+// the encoded entry holds duplicate keys that log backends resolve
+// differently.
+type DuplicatePolicy int8
+
+const (
+	// KeepDuplicates logs every field, as zap does.
+	KeepDuplicates DuplicatePolicy = iota
+	// FirstWins logs the first field with a key; extracted fields come before
+	// call-site fields.
+	FirstWins
+	// LastWins logs the last field with a key, so call-site fields replace
+	// extracted ones.
+	LastWins
+	// CallSiteWins drops extracted fields whose key is passed at the call site,
+	// and keeps the first of the extracted fields sharing a key.
+	CallSiteWins
+	// SuffixDuplicates logs every field and renames repeated keys to key_2,
+	// key_3, and so on.
+	SuffixDuplicates
+)
+
+// DuplicateKeys sets the policy for fields sharing a key. It applies to the
+// extracted and call-site fields of each entry up to the first zap.Namespace,
+// which nests the fields after it; fields added with the underlying logger's
+// With and carrier fields are not considered.
+func DuplicateKeys(policy DuplicatePolicy) Option {
+	return func(o *options) {
+		o.duplicates = policy
+	}
+}
+
+var duplicatePolicyNames = [...]string{
+	KeepDuplicates:   "keep",
+	FirstWins:        "first",
+	LastWins:         "last",
+	CallSiteWins:     "call_site",
+	SuffixDuplicates: "suffix",
+}
+
+// String returns the policy name used by UnmarshalText.
+func (p DuplicatePolicy) String() string {
+	if p >= 0 && int(p) < len(duplicatePolicyNames) {
+		return duplicatePolicyNames[p]
+	}
+
+	return "DuplicatePolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// MarshalText marshals the policy to its name.
+func (p DuplicatePolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses a policy name: keep, first, last, call_site, or
+// suffix. An empty name selects KeepDuplicates.
+func (p *DuplicatePolicy) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = KeepDuplicates
+		return nil
+	}
+
+	for policy, name := range duplicatePolicyNames {
+		if string(text) == name {
+			*p = DuplicatePolicy(policy)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unrecognized duplicate policy: %q", text)
+}
+
+// resolve applies the policy to fields, of which the first extracted are
+// extracted fields.
+func (p DuplicatePolicy) resolve(fields []zap.Field, extracted int) []zap.Field {
+	end := len(fields)
+
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			end = i
+			break
+		}
+	}
+
+	resolved := make([]zap.Field, 0, len(fields))
+
+	switch p {
+	case FirstWins, CallSiteWins:
+		seen := make(map[string]struct{}, end)
+
+		if p == CallSiteWins {
+			for _, f := range fields[min(extracted, end):end] {
+				if dedupable(f) {
+					seen[f.Key] = struct{}{}
+				}
+			}
+		}
+
+		for i, f := range fields[:end] {
+			if !dedupable(f) || (p == CallSiteWins && i >= extracted) {
+				resolved = append(resolved, f)
+				continue
+			}
+
+			if _, ok := seen[f.Key]; ok {
+				continue
+			}
+
+			seen[f.Key] = struct{}{}
+			resolved = append(resolved, f)
+		}
+	case LastWins:
+		last := make(map[string]int, end)
+
+		for i, f := range fields[:end] {
+			if dedupable(f) {
+				last[f.Key] = i
+			}
+		}
+
+		for i, f := range fields[:end] {
+			if !dedupable(f) || last[f.Key] == i {
+				resolved = append(resolved, f)
+			}
+		}
+	case SuffixDuplicates:
+		counts := make(map[string]int, end)
+
+		for _, f := range fields[:end] {
+			if dedupable(f) {
+				counts[f.Key]++
+				if n := counts[f.Key]; n > 1 {
+					f.Key += "_" + strconv.Itoa(n)
+				}
+			}
+
+			resolved = append(resolved, f)
+		}
+	default:
+		return fields
+	}
+
+	return append(resolved, fields[end:]...)
+}
+
+// dedupable reports whether the policy applies to f. Carrier fields are not
+// encoded and inline fields add keys of their own.
+func dedupable(f zap.Field) bool {
+	return f.Type != zapcore.SkipType && f.Type != zapcore.InlineMarshalerType
+}
@@ -0,0 +1,120 @@
+package contextlogger
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDuplicateKeys(t *testing.T) {
+	traceKey := contextKeyString("trace_id")
+	ctx := context.WithValue(context.Background(), traceKey, "otel")
+	ctx = WithFields(ctx, zap.String("trace_id", "sentry"), zap.String("user", "u-1"))
+
+	tests := []struct {
+		policy DuplicatePolicy
+		want   []zap.Field
+	}{
+		{
+			policy: KeepDuplicates,
+			want: []zap.Field{
+				zap.Any("trace_id", "otel"), zap.String("trace_id", "sentry"), zap.String("user", "u-1"),
+				zap.String("trace_id", "call"), zap.String("msg_id", "m-1"),
+			},
+		},
+		{
+			policy: FirstWins,
+			want: []zap.Field{
+				zap.Any("trace_id", "otel"), zap.String("user", "u-1"), zap.String("msg_id", "m-1"),
+			},
+		},
+		{
+			policy: LastWins,
+			want: []zap.Field{
+				zap.String("user", "u-1"), zap.String("trace_id", "call"), zap.String("msg_id", "m-1"),
+			},
+		},
+		{
+			policy: CallSiteWins,
+			want: []zap.Field{
+				zap.String("user", "u-1"), zap.String("trace_id", "call"), zap.String("msg_id", "m-1"),
+			},
+		},
+		{
+			policy: SuffixDuplicates,
+			want: []zap.Field{
+				zap.Any("trace_id", "otel"), zap.String("trace_id_2", "sentry"), zap.String("user", "u-1"),
+				zap.String("trace_id_3", "call"), zap.String("msg_id", "m-1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			core, observed := observer.New(zap.InfoLevel)
+			cl := New(zap.New(core), WithValueExtractor(traceKey), WithFieldsExtractor()).
+				WithOptions(DuplicateKeys(tt.policy))
+
+			cl.Info(ctx, "dup", zap.String("trace_id", "call"), zap.String("msg_id", "m-1"))
+
+			entries := observed.TakeAll()
+			require.Len(t, entries, 1)
+			require.Equal(t, tt.want, entries[0].Context)
+		})
+	}
+
+	t.Run("call site wins keeps first extracted field", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		cl := New(zap.New(core), WithValueExtractor(traceKey), WithFieldsExtractor()).
+			WithOptions(DuplicateKeys(CallSiteWins))
+
+		cl.Ctx(ctx).Info("no call-site trace")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, []zap.Field{zap.Any("trace_id", "otel"), zap.String("user", "u-1")}, entries[0].Context)
+	})
+
+	t.Run("stops at namespaces and skips carriers", func(t *testing.T) {
+		fields := []zap.Field{
+			ContextField("carrier", ctx),
+			zap.String("a", "1"),
+			ContextField("carrier", ctx),
+			zap.String("a", "2"),
+			zap.Namespace("group"),
+			zap.String("a", "3"),
+		}
+
+		resolved := FirstWins.resolve(fields, 2)
+
+		require.Equal(t, []zap.Field{fields[0], fields[1], fields[2], fields[4], fields[5]}, resolved)
+		require.Len(t, fields, 6)
+		require.Equal(t, zapcore.SkipType, resolved[2].Type)
+	})
+}
+
+func TestDuplicatePolicy_Text(t *testing.T) {
+	for _, policy := range []DuplicatePolicy{KeepDuplicates, FirstWins, LastWins, CallSiteWins, SuffixDuplicates} {
+		text, err := policy.MarshalText()
+		require.NoError(t, err)
+
+		var parsed DuplicatePolicy
+		require.NoError(t, parsed.UnmarshalText(text))
+		require.Equal(t, policy, parsed)
+	}
+
+	var policy DuplicatePolicy
+	require.NoError(t, json.Unmarshal([]byte(`"call_site"`), &policy))
+	require.Equal(t, CallSiteWins, policy)
+
+	require.NoError(t, policy.UnmarshalText(nil))
+	require.Equal(t, KeepDuplicates, policy)
+
+	require.Error(t, policy.UnmarshalText([]byte("newest")))
+	require.Equal(t, "DuplicatePolicy(9)", DuplicatePolicy(9).String())
+}
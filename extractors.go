@@ -0,0 +1,380 @@
+package contextlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// costSampleRate is how often extractor calls are timed; timing every call
+// would cost more than many extractors.
+const costSampleRate = 16
+
+// NamedExtractor is an extractor that can be replaced, enabled, or disabled
+// by name while the program runs, such as an expensive extractor switched on
+// during an incident.
+type NamedExtractor struct {
+	Name      string
+	Extractor ContextExtractor
+	// Disabled adds the extractor switched off; see SetEnabled.
+	Disabled bool
+	// Fields declares the fields the extractor may add, for Schema. Schema
+	// lists extractors with nil Fields as undeclared; an empty, non-nil
+	// slice declares that the extractor adds none.
+	Fields []FieldSpec
+
+	// spec and options record how a registry extractor was built, so it can
+	// be rebuilt with other options.
+	spec    string
+	options map[string]string
+}
+
+// ExtractorStats describes an extractor of a ContextLogger.
+type ExtractorStats struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// Calls counts the calls since the extractor was added.
+	Calls uint64 `json:"calls"`
+	// AvgCost is the average duration of a sample of the calls.
+	AvgCost time.Duration `json:"avgCostNs"`
+	// Options are the options a registry extractor was built with.
+	Options map[string]string `json:"options,omitempty"`
+	// Reconfigurable reports whether the options can be changed with
+	// ReconfigureExtractor.
+	Reconfigurable bool `json:"reconfigurable"`
+}
+
+// extractorSet holds the extractors a ContextLogger added on top of the ones
+// of the logger it was derived from. Loggers derived with With share their
+// parent's set, so changes to it apply to them too.
+//
+// Extractors added with With are kept in plain, without names, switches, or
+// per-extractor statistics, because derived loggers are often created per
+// request. Every run calls all of them, so plainCalls counts their calls until
+// their entries are created, the first time they are listed, toggled, or
+// replaced.
+type extractorSet struct {
+	parent     *extractorSet
+	plain      []ContextExtractor
+	plainCalls atomic.Uint64
+	mu         sync.Mutex // serializes writers
+	entries    atomic.Pointer[[]*extractorEntry]
+}
+
+type extractorEntry struct {
+	name    string
+	extract ContextExtractor
+	fields  []FieldSpec
+	spec    string
+	options map[string]string
+	enabled atomic.Bool
+	calls   atomic.Uint64
+	sampled atomic.Uint64
+	cost    atomic.Int64
+	// plainCalls counts the calls made before the entry was created from an
+	// extractor added with With.
+	plainCalls *atomic.Uint64
+}
+
+func newExtractorSet(parent *extractorSet, named []NamedExtractor) *extractorSet {
+	s := &extractorSet{parent: parent}
+	s.store(named)
+
+	return s
+}
+
+// store atomically replaces the entries of s. Nil extractors are skipped.
+func (s *extractorSet) store(named []NamedExtractor) {
+	entries := newEntries(named)
+
+	s.mu.Lock()
+	s.entries.Store(&entries)
+	s.mu.Unlock()
+}
+
+func newEntries(named []NamedExtractor) []*extractorEntry {
+	entries := make([]*extractorEntry, 0, len(named))
+
+	for _, n := range named {
+		if n.Extractor == nil {
+			continue
+		}
+
+		entry := &extractorEntry{
+			name:    n.Name,
+			extract: n.Extractor,
+			fields:  n.Fields,
+			spec:    n.spec,
+			options: n.options,
+		}
+		entry.enabled.Store(!n.Disabled)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// list returns the entries of s, creating them for extractors added with
+// With on first use. It must be called with s.mu held.
+func (s *extractorSet) list() []*extractorEntry {
+	if entries := s.entries.Load(); entries != nil {
+		return *entries
+	}
+
+	entries := newEntries(named(s.plain))
+	for _, entry := range entries {
+		entry.plainCalls = &s.plainCalls
+	}
+
+	s.entries.Store(&entries)
+
+	return entries
+}
+
+// load returns the entries of s like list, locking s.
+func (s *extractorSet) load() []*extractorEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.list()
+}
+
+// size returns the number of extractors in s and its ancestors.
+func (s *extractorSet) size() int {
+	n := 0
+	for ; s != nil; s = s.parent {
+		if entries := s.entries.Load(); entries != nil {
+			n += len(*entries)
+		} else {
+			n += len(s.plain)
+		}
+	}
+
+	return n
+}
+
+// run appends the fields of the enabled extractors, ancestors first.
+func (s *extractorSet) run(ctx context.Context, extracted []zap.Field) []zap.Field {
+	if s.parent != nil {
+		extracted = s.parent.run(ctx, extracted)
+	}
+
+	entries := s.entries.Load()
+	if entries == nil {
+		if len(s.plain) > 0 {
+			s.plainCalls.Add(1)
+		}
+
+		for _, f := range s.plain {
+			fields, _ := callExtractor(ctx, "", f)
+			extracted = append(extracted, fields...)
+		}
+
+		return extracted
+	}
+
+	for _, entry := range *entries {
+		if !entry.enabled.Load() {
+			continue
+		}
+
+		extracted = append(extracted, entry.call(ctx)...)
+	}
+
+	return extracted
+}
+
+// call runs the extractor, counting the call and timing a sample of calls.
+func (e *extractorEntry) call(ctx context.Context) []zap.Field {
+	if e.calls.Add(1)%costSampleRate != 1 {
+		fields, _ := callExtractor(ctx, e.name, e.extract)
+		return fields
+	}
+
+	start := time.Now()
+	fields, _ := callExtractor(ctx, e.name, e.extract)
+	e.cost.Add(int64(time.Since(start)))
+	e.sampled.Add(1)
+
+	return fields
+}
+
+func (e *extractorEntry) stats() ExtractorStats {
+	stats := ExtractorStats{
+		Name:           e.Name(),
+		Enabled:        e.enabled.Load(),
+		Calls:          e.calls.Load(),
+		Options:        copyNames(e.options),
+		Reconfigurable: e.spec != "",
+	}
+
+	if e.plainCalls != nil {
+		stats.Calls += e.plainCalls.Load()
+	}
+
+	if sampled := e.sampled.Load(); sampled > 0 {
+		stats.AvgCost = time.Duration(e.cost.Load() / int64(sampled))
+	}
+
+	return stats
+}
+
+// Name returns the entry's name, naming unnamed extractors after the function
+// implementing them.
+func (e *extractorEntry) Name() string {
+	if e.name == "" {
+		return extractorName(e.extract)
+	}
+
+	return e.name
+}
+
+// named wraps extractors added without a name.
+func named(extractors []ContextExtractor) []NamedExtractor {
+	named := make([]NamedExtractor, len(extractors))
+	for i, f := range extractors {
+		named[i] = NamedExtractor{Extractor: f}
+	}
+
+	return named
+}
+
+// WithNamed returns a new ContextLogger with the additional named
+// extractors, like With.
+func (c *ContextLogger) WithNamed(extractors ...NamedExtractor) *ContextLogger {
+	if len(extractors) == 0 {
+		return c
+	}
+
+	clone := *c
+	clone.extractors = newExtractorSet(c.extractors, extractors)
+
+	return &clone
+}
+
+// SetExtractors atomically replaces the extractors the ContextLogger was
+// created or derived with. Loggers derived from it with With, and copies made
+// with WithOptions, use the new extractors for entries written afterwards,
+// while extractors inherited from the logger it was derived from are kept.
+func (c *ContextLogger) SetExtractors(extractors ...NamedExtractor) {
+	c.extractors.store(extractors)
+}
+
+// SetEnabled enables or disables every extractor named name, including
+// inherited ones, and reports whether any was found. Extractors added with
+// New or With are named after the function implementing them, such as
+// "context-logger.WithDeadlineExtractor.func1"; use WithNamed or
+// SetExtractors for stable names.
+func (c *ContextLogger) SetEnabled(name string, enabled bool) bool {
+	found := false
+
+	for s := c.extractors; s != nil; s = s.parent {
+		s.mu.Lock()
+
+		for _, entry := range s.list() {
+			if entry.Name() == name {
+				entry.enabled.Store(enabled)
+				found = true
+			}
+		}
+
+		s.mu.Unlock()
+	}
+
+	return found
+}
+
+// ExtractorStats describes the extractors of the ContextLogger in the order
+// they run, inherited ones first.
+func (c *ContextLogger) ExtractorStats() []ExtractorStats {
+	var sets []*extractorSet
+	for s := c.extractors; s != nil; s = s.parent {
+		sets = append(sets, s)
+	}
+
+	stats := make([]ExtractorStats, 0, c.extractors.size())
+
+	for i := len(sets) - 1; i >= 0; i-- {
+		for _, entry := range sets[i].load() {
+			stats = append(stats, entry.stats())
+		}
+	}
+
+	return stats
+}
+
+// ErrExtractorNotFound is returned for an extractor name the ContextLogger
+// does not have.
+var ErrExtractorNotFound = errors.New("extractor not found")
+
+// ReconfigureExtractor rebuilds every extractor named name, including
+// inherited ones, with options replacing the ones it was built with. Only
+// extractors built from the registry, such as by NewFromConfig, can be
+// rebuilt. Their enabled state is kept and their statistics restart.
+func (c *ContextLogger) ReconfigureExtractor(name string, options map[string]string) error {
+	found := false
+
+	for s := c.extractors; s != nil; s = s.parent {
+		ok, err := s.reconfigure(name, options)
+		if err != nil {
+			return err
+		}
+
+		found = found || ok
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %q", ErrExtractorNotFound, name)
+	}
+
+	return nil
+}
+
+// reconfigure replaces the entries of s named name with ones rebuilt with
+// options, reporting whether any was found.
+func (s *extractorSet) reconfigure(name string, options map[string]string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.list()
+	entries := make([]*extractorEntry, len(current))
+	found := false
+
+	for i, entry := range current {
+		entries[i] = entry
+		if entry.Name() != name {
+			continue
+		}
+
+		if entry.spec == "" {
+			return false, fmt.Errorf("extractor %q was not built from the registry", name)
+		}
+
+		named, err := NewNamedExtractor(entry.spec, options)
+		if err != nil {
+			return false, err
+		}
+
+		rebuilt := &extractorEntry{
+			name:    entry.name,
+			extract: named.Extractor,
+			fields:  named.Fields,
+			spec:    entry.spec,
+			options: named.options,
+		}
+		rebuilt.enabled.Store(entry.enabled.Load())
+		entries[i] = rebuilt
+		found = true
+	}
+
+	if found {
+		s.entries.Store(&entries)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,124 @@
+package contextlogger
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContextLogger_Reconfiguration(t *testing.T) {
+	userKey := contextKeyString("user_id")
+	headersKey := contextKeyString("headers")
+	ctx := context.WithValue(context.Background(), userKey, "u-1")
+	ctx = context.WithValue(ctx, headersKey, "accept: */*")
+
+	t.Run("toggles extractors by name in derived loggers", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		base := New(logger).WithNamed(
+			NamedExtractor{Name: "user", Extractor: WithValueExtractor(userKey)},
+			NamedExtractor{Name: "headers", Extractor: WithValueExtractor(headersKey), Disabled: true},
+		)
+		derived := base.With(WithDeadlineExtractor()).WithOptions(DuplicateKeys(FirstWins))
+
+		fields := logAndAssert(t, ctx, observed, derived, "disabled")
+		require.Equal(t, "u-1", fields["user_id"])
+		require.NotContains(t, fields, "headers")
+
+		require.True(t, base.SetEnabled("headers", true))
+		require.Equal(t, "accept: */*", logAndAssert(t, ctx, observed, derived, "enabled")["headers"])
+
+		require.True(t, derived.SetEnabled("user", false))
+		require.NotContains(t, logAndAssert(t, ctx, observed, base, "inherited"), "user_id")
+
+		require.False(t, base.SetEnabled("missing", true))
+	})
+
+	t.Run("replaces extractors for derived loggers", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		base := New(logger, WithValueExtractor(userKey))
+		derived := base.With(WithFieldsExtractor())
+		copied := base.WithOptions()
+
+		base.SetExtractors(NamedExtractor{Name: "headers", Extractor: WithValueExtractor(headersKey)})
+
+		for _, cl := range []*ContextLogger{base, derived, copied} {
+			fields := logAndAssert(t, WithFields(ctx, zap.Int("n", 1)), observed, cl, "replaced")
+			require.Equal(t, "accept: */*", fields["headers"])
+			require.NotContains(t, fields, "user_id")
+		}
+
+		require.Equal(t, int64(1), logAndAssert(t, WithFields(ctx, zap.Int("n", 1)), observed, derived, "own")["n"])
+
+		derived.SetExtractors()
+		fields := logAndAssert(t, WithFields(ctx, zap.Int("n", 1)), observed, derived, "inherited kept")
+		require.Equal(t, "accept: */*", fields["headers"])
+		require.NotContains(t, fields, "n")
+	})
+
+	t.Run("starts logging once extractors are set", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger)
+
+		require.NotContains(t, logAndAssert(t, ctx, observed, cl, "none"), "user_id")
+
+		cl.SetExtractors(NamedExtractor{Name: "user", Extractor: WithValueExtractor(userKey)}, NamedExtractor{Name: "nil"})
+		cl.Info(ctx, "level method")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "u-1", entries[0].ContextMap()["user_id"])
+	})
+
+	t.Run("names unnamed extractors after their function", func(t *testing.T) {
+		cl := New(nil, WithDeadlineExtractor(), panickingExtractor)
+
+		require.True(t, cl.SetEnabled("context-logger.panickingExtractor", false))
+		require.Empty(t, cl.run(context.Background(), 0))
+	})
+
+	t.Run("counts calls of extractors added with With from the start", func(t *testing.T) {
+		logger, _ := newTestLogger()
+		child := New(logger).With(WithDeadlineExtractor())
+
+		for range 10 {
+			child.Info(ctx, "counted")
+		}
+
+		require.Equal(t, uint64(10), child.ExtractorStats()[0].Calls)
+
+		child.Info(ctx, "counted")
+		require.Equal(t, uint64(11), child.ExtractorStats()[0].Calls)
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		logger, _ := newTestLogger()
+		cl := New(logger).WithNamed(NamedExtractor{Name: "user", Extractor: WithValueExtractor(userKey)})
+		derived := cl.With(WithDeadlineExtractor())
+
+		var wg sync.WaitGroup
+
+		for i := range 8 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for j := range 100 {
+					switch (i + j) % 3 {
+					case 0:
+						cl.SetEnabled("user", j%2 == 0)
+					case 1:
+						cl.SetExtractors(NamedExtractor{Name: "user", Extractor: WithValueExtractor(userKey)})
+					default:
+						derived.Info(ctx, "concurrent")
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+}
@@ -0,0 +1,58 @@
+package contextlogger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type fieldsContextKey struct{}
+
+// WithFields returns a copy of ctx with fields added to the ones accumulated
+// by earlier calls along the context chain. A field replaces an accumulated
+// field with the same key, keeping its position. A nil context is treated as
+// context.Background().
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	accumulated := contextFields(ctx)
+	merged := make([]zap.Field, len(accumulated), len(accumulated)+len(fields))
+	copy(merged, accumulated)
+
+	for _, f := range fields {
+		merged = setField(merged, f)
+	}
+
+	return context.WithValue(ctx, fieldsContextKey{}, merged)
+}
+
+// WithFieldsExtractor adds the fields accumulated with WithFields, in the order
+// their keys were first added.
+func WithFieldsExtractor() ContextExtractor {
+	return contextFields
+}
+
+// contextFields returns the fields accumulated in ctx. The result is shared
+// and must not be modified.
+func contextFields(ctx context.Context) []zap.Field {
+	fields, _ := ctx.Value(fieldsContextKey{}).([]zap.Field)
+	return fields
+}
+
+// setField replaces the field with f's key in fields, or appends f.
+func setField(fields []zap.Field, f zap.Field) []zap.Field {
+	for i := range fields {
+		if fields[i].Key == f.Key {
+			fields[i] = f
+			return fields
+		}
+	}
+
+	return append(fields, f)
+}
@@ -0,0 +1,62 @@
+package contextlogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithFields(t *testing.T) {
+	logger, observed := newTestLogger()
+	cl := New(logger, WithFieldsExtractor())
+
+	t.Run("emits accumulated fields in order", func(t *testing.T) {
+		observed.TakeAll()
+		ctx := WithFields(context.Background(), zap.String("tenant_id", "t-1"))
+		ctx = WithFields(ctx, zap.String("user_id", "u-1"), zap.Int("order_id", 7))
+
+		cl.Ctx(ctx).Info("accumulated")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		keys := make([]string, 0, len(entries[0].Context))
+		for _, f := range entries[0].Context {
+			keys = append(keys, f.Key)
+		}
+		require.Equal(t, []string{"text", "tenant_id", "user_id", "order_id"}, keys)
+	})
+
+	t.Run("later values override earlier ones by key", func(t *testing.T) {
+		observed.TakeAll()
+		parent := WithFields(context.Background(), zap.String("user_id", "anonymous"), zap.String("tenant_id", "t-1"))
+		child := WithFields(parent, zap.String("user_id", "u-42"), zap.String("user_id", "u-43"))
+
+		fields := logAndAssert(t, child, observed, cl, "override")
+		require.Equal(t, "u-43", fields["user_id"])
+		require.Equal(t, "t-1", fields["tenant_id"])
+	})
+
+	t.Run("does not modify the parent context", func(t *testing.T) {
+		observed.TakeAll()
+		parent := WithFields(context.Background(), zap.String("user_id", "anonymous"))
+		_ = WithFields(parent, zap.String("user_id", "u-42"), zap.String("extra", "x"))
+
+		fields := logAndAssert(t, parent, observed, cl, "parent")
+		require.Equal(t, "anonymous", fields["user_id"])
+		_, ok := fields["extra"]
+		require.False(t, ok)
+	})
+
+	t.Run("returns ctx without fields", func(t *testing.T) {
+		ctx := context.Background()
+
+		require.Equal(t, ctx, WithFields(ctx))
+		require.NotNil(t, WithFields(nil))
+	})
+
+	t.Run("emits nothing without accumulated fields", func(t *testing.T) {
+		require.Empty(t, WithFieldsExtractor()(context.Background()))
+	})
+}
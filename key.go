@@ -0,0 +1,184 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Key is a typed context key that stores, retrieves, and logs values of type
+// T under a field name. Each Key returned by NewKey is a distinct context key.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a Key logged under name. Values are encoded with the zap
+// field constructor for T, such as zap.String or zap.Duration, falling back to
+// zap.Object, zap.Array, or zap.Stringer when T implements the matching
+// interface, and to zap.Any otherwise. Values implementing LogFielder add
+// their fields inline.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the field name, so a Key also works with WithValueExtractor,
+// PropagateValues, and PropagateValue.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// typedKey is implemented by Key, so PropagateValues restores only the keys
+// that hold strings.
+type typedKey interface {
+	valueType() reflect.Type
+}
+
+func (k *Key[T]) valueType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// Set returns a copy of ctx carrying value under k. A nil context is treated
+// as context.Background().
+func (k *Key[T]) Set(ctx context.Context, value T) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, k, value)
+}
+
+// Get returns the value stored under k and reports whether it was present.
+func (k *Key[T]) Get(ctx context.Context) (T, bool) {
+	if ctx == nil {
+		var zero T
+		return zero, false
+	}
+
+	value, ok := ctx.Value(k).(T)
+
+	return value, ok
+}
+
+// Extractor returns an extractor that adds the value stored under k, if any.
+func (k *Key[T]) Extractor() ContextExtractor {
+	return WithAccessor(k.name, k.Get, nil)
+}
+
+// WithAccessor adds the value returned by get under fieldName when get reports
+// it as present. It suits values that other libraries keep under unexported
+// context keys and expose only through getters. A nil encode selects the
+// field constructor for T as NewKey does, and adds the fields of values
+// implementing LogFielder inline.
+func WithAccessor[T any](
+	fieldName string,
+	get func(context.Context) (T, bool),
+	encode func(string, T) zap.Field,
+) ContextExtractor {
+	describes := false
+	if encode == nil {
+		encode = fieldEncoder[T]()
+		describes = mayLogFields[T]()
+	}
+
+	return func(ctx context.Context) []zap.Field {
+		if get == nil {
+			return nil
+		}
+
+		value, ok := get(ctx)
+		if !ok {
+			return nil
+		}
+
+		if describes {
+			if fielder, ok := any(value).(LogFielder); ok {
+				return fielder.LogFields()
+			}
+		}
+
+		return []zap.Field{encode(fieldName, value)}
+	}
+}
+
+var logFielderType = reflect.TypeFor[LogFielder]()
+
+// mayLogFields reports whether values of type T can implement LogFielder.
+func mayLogFields[T any]() bool {
+	typ := reflect.TypeFor[T]()
+	return typ.Kind() == reflect.Interface || typ.Implements(logFielderType)
+}
+
+// fieldEncoder returns the zap field constructor specialized for T.
+func fieldEncoder[T any]() func(string, T) zap.Field {
+	var encoder any
+
+	switch any((*T)(nil)).(type) {
+	case *string:
+		encoder = zap.String
+	case *bool:
+		encoder = zap.Bool
+	case *int:
+		encoder = zap.Int
+	case *int8:
+		encoder = zap.Int8
+	case *int16:
+		encoder = zap.Int16
+	case *int32:
+		encoder = zap.Int32
+	case *int64:
+		encoder = zap.Int64
+	case *uint:
+		encoder = zap.Uint
+	case *uint8:
+		encoder = zap.Uint8
+	case *uint16:
+		encoder = zap.Uint16
+	case *uint32:
+		encoder = zap.Uint32
+	case *uint64:
+		encoder = zap.Uint64
+	case *float32:
+		encoder = zap.Float32
+	case *float64:
+		encoder = zap.Float64
+	case *[]byte:
+		encoder = zap.ByteString
+	case *[]string:
+		encoder = zap.Strings
+	case *time.Time:
+		encoder = zap.Time
+	case *time.Duration:
+		encoder = zap.Duration
+	case *error:
+		encoder = zap.NamedError
+	}
+
+	if encode, ok := encoder.(func(string, T) zap.Field); ok {
+		return encode
+	}
+
+	var zero T
+
+	switch any(zero).(type) {
+	case zapcore.ObjectMarshaler:
+		return func(key string, value T) zap.Field {
+			return zap.Object(key, any(value).(zapcore.ObjectMarshaler))
+		}
+	case zapcore.ArrayMarshaler:
+		return func(key string, value T) zap.Field {
+			return zap.Array(key, any(value).(zapcore.ArrayMarshaler))
+		}
+	case fmt.Stringer:
+		return func(key string, value T) zap.Field {
+			return zap.Stringer(key, any(value).(fmt.Stringer))
+		}
+	default:
+		return func(key string, value T) zap.Field {
+			return zap.Any(key, value)
+		}
+	}
+}
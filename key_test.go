@@ -0,0 +1,195 @@
+package contextlogger
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type testPrincipal struct {
+	id string
+}
+
+func (p testPrincipal) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", p.id)
+	return nil
+}
+
+type testRoles []string
+
+func (r testRoles) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, role := range r {
+		enc.AppendString(role)
+	}
+
+	return nil
+}
+
+type testPlain struct {
+	V int
+}
+
+func TestKey(t *testing.T) {
+	t.Run("sets gets and extracts", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		requestID := NewKey[string]("request_id")
+		cl := New(logger, requestID.Extractor())
+
+		ctx := requestID.Set(context.Background(), "req-1")
+		value, ok := requestID.Get(ctx)
+		require.True(t, ok)
+		require.Equal(t, "req-1", value)
+
+		fields := logAndAssert(t, ctx, observed, cl, "typed-key")
+		require.Equal(t, "req-1", fields["request_id"])
+	})
+
+	t.Run("keys with the same name are distinct", func(t *testing.T) {
+		first := NewKey[string]("id")
+		second := NewKey[string]("id")
+
+		ctx := first.Set(context.Background(), "first")
+		_, ok := second.Get(ctx)
+		require.False(t, ok)
+	})
+
+	t.Run("extracts nothing when absent", func(t *testing.T) {
+		key := NewKey[int]("attempt")
+
+		require.Nil(t, key.Extractor()(context.Background()))
+		_, ok := key.Get(nil)
+		require.False(t, ok)
+		require.NotNil(t, key.Set(nil, 1))
+	})
+
+	t.Run("works as a value extractor key", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		key := NewKey[string]("tenant_id")
+		cl := New(logger, WithValueExtractor(key))
+
+		fields := logAndAssert(t, key.Set(context.Background(), "t-1"), observed, cl, "stringer-key")
+		require.Equal(t, "t-1", fields["tenant_id"])
+		require.Equal(t, "tenant_id", key.String())
+	})
+}
+
+func TestKey_FieldEncoding(t *testing.T) {
+	now := time.Now()
+	err := errors.New("boom")
+
+	tests := []struct {
+		name  string
+		field zap.Field
+		want  zap.Field
+	}{
+		{"string", extractOne(t, NewKey[string]("k"), "v"), zap.String("k", "v")},
+		{"bool", extractOne(t, NewKey[bool]("k"), true), zap.Bool("k", true)},
+		{"int", extractOne(t, NewKey[int]("k"), 1), zap.Int("k", 1)},
+		{"int8", extractOne(t, NewKey[int8]("k"), 1), zap.Int8("k", 1)},
+		{"int16", extractOne(t, NewKey[int16]("k"), 1), zap.Int16("k", 1)},
+		{"int32", extractOne(t, NewKey[int32]("k"), 1), zap.Int32("k", 1)},
+		{"int64", extractOne(t, NewKey[int64]("k"), 1), zap.Int64("k", 1)},
+		{"uint", extractOne(t, NewKey[uint]("k"), 1), zap.Uint("k", 1)},
+		{"uint8", extractOne(t, NewKey[uint8]("k"), 1), zap.Uint8("k", 1)},
+		{"uint16", extractOne(t, NewKey[uint16]("k"), 1), zap.Uint16("k", 1)},
+		{"uint32", extractOne(t, NewKey[uint32]("k"), 1), zap.Uint32("k", 1)},
+		{"uint64", extractOne(t, NewKey[uint64]("k"), 1), zap.Uint64("k", 1)},
+		{"float32", extractOne(t, NewKey[float32]("k"), 1.5), zap.Float32("k", 1.5)},
+		{"float64", extractOne(t, NewKey[float64]("k"), 1.5), zap.Float64("k", 1.5)},
+		{"bytes", extractOne(t, NewKey[[]byte]("k"), []byte("v")), zap.ByteString("k", []byte("v"))},
+		{"strings", extractOne(t, NewKey[[]string]("k"), []string{"v"}), zap.Strings("k", []string{"v"})},
+		{"time", extractOne(t, NewKey[time.Time]("k"), now), zap.Time("k", now)},
+		{"duration", extractOne(t, NewKey[time.Duration]("k"), time.Second), zap.Duration("k", time.Second)},
+		{"error", extractOne(t, NewKey[error]("k"), err), zap.NamedError("k", err)},
+		{"object", extractOne(t, NewKey[testPrincipal]("k"), testPrincipal{id: "p"}), zap.Object("k", testPrincipal{id: "p"})},
+		{"array", extractOne(t, NewKey[testRoles]("k"), testRoles{"admin"}), zap.Array("k", testRoles{"admin"})},
+		{"stringer", extractOne(t, NewKey[time.Month]("k"), time.March), zap.Stringer("k", time.March)},
+		{"any", extractOne(t, NewKey[testPlain]("k"), testPlain{V: 1}), zap.Any("k", testPlain{V: 1})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want.Type, tt.field.Type)
+			require.True(t, tt.want.Equals(tt.field))
+		})
+	}
+}
+
+func extractOne[T any](t *testing.T, key *Key[T], value T) zap.Field {
+	t.Helper()
+
+	fields := key.Extractor()(key.Set(context.Background(), value))
+	require.Len(t, fields, 1)
+
+	return fields[0]
+}
+
+type foreignKey struct{}
+
+func foreignUserID(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(foreignKey{}).(int64)
+	return id, ok
+}
+
+func TestWithAccessor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), foreignKey{}, int64(42))
+
+	t.Run("logs values exposed by a getter", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithAccessor("user_id", foreignUserID, nil))
+
+		cl.Ctx(ctx).Info("accessor")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, int64(42), entries[0].ContextMap()["user_id"])
+		require.Equal(t, zapcore.Int64Type, entries[0].Context[1].Type)
+	})
+
+	t.Run("uses custom encoder", func(t *testing.T) {
+		extract := WithAccessor("user", foreignUserID, func(key string, id int64) zap.Field {
+			return zap.String(key, "u-"+strconv.FormatInt(id, 10))
+		})
+
+		require.Equal(t, []zap.Field{zap.String("user", "u-42")}, extract(ctx))
+	})
+
+	t.Run("skips absent values and nil getter", func(t *testing.T) {
+		require.Nil(t, WithAccessor("user_id", foreignUserID, nil)(context.Background()))
+		require.Nil(t, WithAccessor[string]("user_id", nil, nil)(ctx))
+	})
+}
+
+func TestKey_LogFielder(t *testing.T) {
+	tenant := testTenant{id: "t-1"}
+
+	t.Run("adds fields inline for concrete types", func(t *testing.T) {
+		key := NewKey[testTenant]("tenant")
+
+		fields := key.Extractor()(key.Set(context.Background(), tenant))
+
+		require.Equal(t, tenant.LogFields(), fields)
+	})
+
+	t.Run("adds fields inline for interface types", func(t *testing.T) {
+		key := NewKey[any]("value")
+
+		require.Equal(t, tenant.LogFields(), key.Extractor()(key.Set(context.Background(), tenant)))
+		require.Equal(t, []zap.Field{zap.Any("value", 1)}, key.Extractor()(key.Set(context.Background(), 1)))
+	})
+
+	t.Run("custom encoder takes precedence", func(t *testing.T) {
+		key := NewKey[testTenant]("tenant")
+		extract := WithAccessor("tenant", key.Get, func(name string, v testTenant) zap.Field {
+			return zap.String(name, v.id)
+		})
+
+		require.Equal(t, []zap.Field{zap.String("tenant", "t-1")}, extract(key.Set(context.Background(), tenant)))
+	})
+}
@@ -0,0 +1,143 @@
+package contextlogger
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldContextTruncated lists the keys of extracted fields that were dropped
+// or truncated by Limits.
+const FieldContextTruncated = "context_truncated"
+
+// Limits caps the size of extracted fields so that one oversized context value
+// cannot push a log entry past the line limit of a log shipper. Zero values
+// disable a limit. Call-site fields and carrier fields are not limited.
+type Limits struct {
+	// MaxFields caps the number of extracted fields; later fields are dropped.
+	// The members of groups, such as those made by Namespace and GroupFields,
+	// count as fields, and groups left empty are dropped.
+	MaxFields int `json:"maxFields" yaml:"maxFields"`
+	// MaxStringBytes truncates string values longer than this many bytes,
+	// without splitting UTF-8 characters.
+	MaxStringBytes int `json:"maxStringBytes" yaml:"maxStringBytes"`
+	// MaxValueBytes drops objects, arrays, errors, binary values, and values
+	// logged with zap.Any whose JSON encoding is longer than this many bytes.
+	// Objects and arrays are measured as they marshal themselves, which stops
+	// as soon as the limit is exceeded; values logged with zap.Any are
+	// encoded with encoding/json to measure them.
+	MaxValueBytes int `json:"maxValueBytes" yaml:"maxValueBytes"`
+	// MaxDepth drops objects, arrays, and values logged with zap.Any that
+	// nest more than this many levels; a flat object has depth 1. Values
+	// logged with zap.Any that fail to encode, such as cyclic ones, are
+	// dropped whenever MaxValueBytes or MaxDepth is set.
+	MaxDepth int `json:"maxDepth" yaml:"maxDepth"`
+}
+
+// LimitFields applies limits to extracted fields. When a field is dropped or
+// truncated, the entry gets a context_truncated field listing its key.
+func LimitFields(limits Limits) Option {
+	return func(o *options) {
+		o.limits = limits
+	}
+}
+
+// apply returns fields with the limits applied, followed by a
+// context_truncated field when anything was dropped or truncated.
+func (l Limits) apply(fields []zap.Field) []zap.Field {
+	count := 0
+
+	limited, affected := l.limit(fields, "", &count, nil)
+	if len(affected) == 0 {
+		return limited
+	}
+
+	return append(limited, zap.Strings(FieldContextTruncated, affected))
+}
+
+// limit applies the limits to fields, recording affected keys prefixed with
+// the enclosing group names. count is the number of fields kept so far,
+// including the members of groups.
+func (l Limits) limit(fields []zap.Field, prefix string, count *int, affected []string) ([]zap.Field, []string) {
+	limited := make([]zap.Field, 0, len(fields))
+
+	for _, field := range fields {
+		if field.Type == zapcore.SkipType {
+			limited = append(limited, field)
+			continue
+		}
+
+		key := prefix + field.Key
+
+		if group, ok := field.Interface.(fieldsObject); ok && field.Type == zapcore.ObjectMarshalerType {
+			var members []zap.Field
+
+			members, affected = l.limit(group, key+".", count, affected)
+			if len(members) > 0 {
+				field.Interface = fieldsObject(members)
+				limited = append(limited, field)
+			}
+
+			continue
+		}
+
+		if l.MaxFields > 0 && *count == l.MaxFields {
+			affected = append(affected, key)
+			continue
+		}
+
+		*count++
+
+		field, changed, keep := l.limitValue(field)
+		if changed {
+			affected = append(affected, key)
+		}
+
+		if keep {
+			limited = append(limited, field)
+		}
+	}
+
+	return limited, affected
+}
+
+// limitValue truncates or drops a single field, reporting whether it changed
+// the field and whether the field is kept.
+func (l Limits) limitValue(field zap.Field) (limited zap.Field, changed, keep bool) {
+	switch field.Type {
+	case zapcore.StringType:
+		if l.MaxStringBytes > 0 && len(field.String) > l.MaxStringBytes {
+			return zap.String(field.Key, truncateBytes(field.String, l.MaxStringBytes)), true, true
+		}
+	case zapcore.ByteStringType, zapcore.StringerType:
+		if l.MaxStringBytes <= 0 {
+			break
+		}
+
+		if value, _ := fieldString(field); len(value) > l.MaxStringBytes {
+			return zap.String(field.Key, truncateBytes(value, l.MaxStringBytes)), true, true
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.InlineMarshalerType,
+		zapcore.ReflectType, zapcore.ErrorType, zapcore.BinaryType:
+		if (l.MaxValueBytes > 0 || l.MaxDepth > 0) && exceeds(field, l.MaxValueBytes, l.MaxDepth) {
+			return field, true, false
+		}
+	}
+
+	return field, false, true
+}
+
+// truncateBytes returns the longest prefix of s that is at most n bytes and
+// does not split a UTF-8 character.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
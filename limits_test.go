@@ -0,0 +1,166 @@
+package contextlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLimitFields(t *testing.T) {
+	t.Run("caps the number of fields", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		ctx := WithFields(context.Background(), zap.Int("a", 1), zap.Int("b", 2), zap.Int("c", 3))
+		cl := New(logger, WithFieldsExtractor(), WithContextCarrier("carrier")).
+			WithOptions(LimitFields(Limits{MaxFields: 2}))
+
+		cl.Info(ctx, "capped", zap.Int("d", 4))
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, int64(1), fields["a"])
+		require.Equal(t, int64(2), fields["b"])
+		require.NotContains(t, fields, "c")
+		require.Equal(t, int64(4), fields["d"])
+		require.Equal(t, []interface{}{"c"}, fields[FieldContextTruncated])
+	})
+
+	t.Run("truncates strings without splitting characters", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		ctx := WithFields(context.Background(),
+			zap.String("body", "héllo world"),
+			zap.Stringer("month", testMonth{}),
+			zap.ByteString("raw", []byte("abcdef")),
+			zap.String("short", "ok"),
+		)
+		cl := New(logger, WithFieldsExtractor()).WithOptions(LimitFields(Limits{MaxStringBytes: 5}))
+
+		fields := logAndAssert(t, ctx, observed, cl, "truncated")
+		require.Equal(t, "héll", fields["body"])
+		require.Equal(t, "Septe", fields["month"])
+		require.Equal(t, "abcde", fields["raw"])
+		require.Equal(t, "ok", fields["short"])
+		require.Equal(t, []interface{}{"body", "month", "raw"}, fields[FieldContextTruncated])
+	})
+
+	t.Run("drops oversized values", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		ctx := WithFields(context.Background(),
+			zap.Any("payload", map[string]string{"body": strings.Repeat("x", 100)}),
+			zap.Any("small", map[string]int{"n": 1}),
+			zap.Error(errors.New(strings.Repeat("e", 100))),
+		)
+		cl := New(logger, WithFieldsExtractor()).WithOptions(LimitFields(Limits{MaxValueBytes: 64}))
+
+		fields := logAndAssert(t, ctx, observed, cl, "dropped")
+		require.NotContains(t, fields, "payload")
+		require.NotContains(t, fields, "error")
+		require.Equal(t, map[string]int{"n": 1}, fields["small"])
+		require.Equal(t, []interface{}{"payload", "error"}, fields[FieldContextTruncated])
+	})
+
+	t.Run("drops values nested too deep", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		ctx := WithFields(context.Background(),
+			zap.Any("deep", map[string]any{"a": map[string]any{"b": map[string]int{"c": 1}}}),
+			zap.Any("flat", map[string]int{"n": 1}),
+		)
+		cl := New(logger, WithFieldsExtractor()).WithOptions(LimitFields(Limits{MaxDepth: 2}))
+
+		fields := logAndAssert(t, ctx, observed, cl, "deep")
+		require.NotContains(t, fields, "deep")
+		require.Equal(t, map[string]int{"n": 1}, fields["flat"])
+		require.Equal(t, []interface{}{"deep"}, fields[FieldContextTruncated])
+	})
+
+	t.Run("stops measuring once a value is too large", func(t *testing.T) {
+		body := &countingArray{n: 1000}
+		limited := Limits{MaxValueBytes: 64}.apply([]zap.Field{zap.Array("body", body)})
+
+		require.Equal(t, []zap.Field{zap.Strings(FieldContextTruncated, []string{"body"})}, limited)
+		require.Less(t, body.appended, 64)
+	})
+
+	t.Run("limits namespace members", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		ctx := WithFields(context.Background(), zap.String("a", "long value"), zap.String("b", "x"))
+		cl := New(logger, Namespace("ctx", WithFieldsExtractor())).
+			WithOptions(LimitFields(Limits{MaxFields: 1, MaxStringBytes: 4}))
+
+		fields := logAndAssert(t, ctx, observed, cl, "grouped")
+		require.Equal(t, map[string]interface{}{"a": "long"}, fields["ctx"])
+		require.Equal(t, []interface{}{"ctx.a", "ctx.b"}, fields[FieldContextTruncated])
+	})
+
+	t.Run("counts grouped fields toward MaxFields", func(t *testing.T) {
+		logger, observed := newTestLogger()
+
+		extracted := make([]zap.Field, 50)
+		for i := range extracted {
+			extracted[i] = zap.Int(fmt.Sprintf("f%02d", i), i)
+		}
+
+		ctx := WithFields(context.Background(), extracted...)
+		cl := New(logger, WithFieldsExtractor()).
+			WithOptions(GroupFields("ctx"), LimitFields(Limits{MaxFields: 3}))
+
+		fields := logAndAssert(t, ctx, observed, cl, "grouped")
+		require.Len(t, fields["ctx"], 3)
+		require.Len(t, fields[FieldContextTruncated], 47)
+	})
+
+	t.Run("drops groups left empty", func(t *testing.T) {
+		limited := Limits{MaxFields: 1}.apply([]zap.Field{
+			zap.String("a", "x"),
+			zap.Object("ctx", fieldsObject{zap.String("b", "y")}),
+		})
+
+		require.Equal(t, []zap.Field{
+			zap.String("a", "x"),
+			zap.Strings(FieldContextTruncated, []string{"ctx.b"}),
+		}, limited)
+	})
+
+	t.Run("adds no marker within limits", func(t *testing.T) {
+		limited := Limits{MaxFields: 5, MaxStringBytes: 5, MaxValueBytes: 64}.apply([]zap.Field{
+			zap.String("a", "ok"),
+			zap.Any("b", []int{1}),
+		})
+
+		require.Len(t, limited, 2)
+		require.Equal(t, zapcore.StringType, limited[0].Type)
+	})
+}
+
+// countingArray appends n strings, stopping early when the encoder signals an
+// error like a marshaler that returns the error of each nested append.
+type countingArray struct {
+	n        int
+	appended int
+}
+
+func (a *countingArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for range a.n {
+		a.appended++
+		if err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("k", "v")
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type testMonth struct{}
+
+func (testMonth) String() string {
+	return "September"
+}
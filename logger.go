@@ -24,10 +24,17 @@ const (
 	FieldContextCause = "context_cause"
 )
 
+// checkerCallerSkip skips the level method and check frames between the
+// caller and zap.Logger.Check.
+const checkerCallerSkip = 2
+
 // ContextLogger attaches fields extracted from a context to a zap logger.
 type ContextLogger struct {
 	logger     *zap.Logger
-	extractors []ContextExtractor
+	checker    *zap.Logger
+	sugared    *zap.SugaredLogger
+	extractors *extractorSet
+	opts       options
 }
 
 // New creates a ContextLogger and falls back to a no-op logger when logger is nil.
@@ -36,11 +43,11 @@ func New(logger *zap.Logger, extractors ...ContextExtractor) *ContextLogger {
 		logger = zap.NewNop()
 	}
 
-	copiedExtractors := append([]ContextExtractor(nil), extractors...)
-
 	return &ContextLogger{
 		logger:     logger,
-		extractors: copiedExtractors,
+		checker:    logger.WithOptions(zap.AddCallerSkip(checkerCallerSkip)),
+		sugared:    logger.Sugar(),
+		extractors: newExtractorSet(nil, named(extractors)),
 	}
 }
 
@@ -49,24 +56,81 @@ func WithContext(logger *zap.Logger, extractors ...ContextExtractor) *ContextLog
 	return New(logger, extractors...)
 }
 
-// Ctx returns the underlying logger with fields extracted from ctx.
-// A nil context is treated as context.Background().
+// Ctx returns the underlying logger bound to ctx. Extractors run only when an
+// entry passes the level check and is written, so disabled levels cost no
+// extraction. A nil context is treated as context.Background().
 func (c *ContextLogger) Ctx(ctx context.Context) *zap.Logger {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	additionalFields := make([]zap.Field, 0, len(c.extractors))
+	if c.extractors.size() == 0 {
+		return c.logger
+	}
 
-	for _, f := range c.extractors {
-		if f == nil {
-			continue
-		}
+	return c.logger.WithOptions(c.bind(ctx))
+}
+
+// CtxSugar returns the sugared underlying logger bound to ctx, like Ctx. A nil
+// context is treated as context.Background().
+func (c *ContextLogger) CtxSugar(ctx context.Context) *zap.SugaredLogger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if c.extractors.size() == 0 {
+		return c.sugared
+	}
+
+	return c.sugared.WithOptions(c.bind(ctx))
+}
+
+// bind returns an option that wraps a logger's core with a contextCore for ctx.
+func (c *ContextLogger) bind(ctx context.Context) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &contextCore{Core: core, logger: c, ctx: ctx}
+	})
+}
+
+// extract returns the fields extracted from ctx followed by fields, with the
+// duplicate policy applied. Fields attached to ctx by Attach or Restore are
+// reused instead of running the extractors.
+func (c *ContextLogger) extract(ctx context.Context, fields []zap.Field) []zap.Field {
+	var merged []zap.Field
+
+	if attached, ok := attachedFields(ctx, c); ok {
+		merged = make([]zap.Field, 0, len(attached)+len(fields))
+		merged = append(merged, attached...)
+	} else {
+		merged = c.run(ctx, len(fields))
+	}
+
+	extracted := len(merged)
+	merged = append(merged, fields...)
 
-		additionalFields = append(additionalFields, f(ctx)...)
+	if c.opts.duplicates == KeepDuplicates {
+		return merged
 	}
 
-	return c.logger.With(additionalFields...)
+	return c.opts.duplicates.resolve(merged, extracted)
+}
+
+// run runs the extractors against ctx, reserving room for extra more fields.
+// A panicking extractor is reported in place of its fields and the remaining
+// extractors still run.
+func (c *ContextLogger) run(ctx context.Context, extra int) []zap.Field {
+	extracted := c.extractors.run(ctx, make([]zap.Field, 0, c.extractors.size()+extra))
+	extracted = nestFields(c.opts.namespace, extracted)
+
+	if len(c.opts.names) > 0 {
+		renameFields(extracted, c.opts.names, c.opts.converters)
+	}
+
+	if c.opts.limits != (Limits{}) {
+		extracted = c.opts.limits.apply(extracted)
+	}
+
+	return extracted
 }
 
 // With returns a new ContextLogger with the additional extractors.
@@ -76,18 +140,103 @@ func (c *ContextLogger) With(extractors ...ContextExtractor) *ContextLogger {
 		return c
 	}
 
-	if len(c.extractors) == 0 {
-		return New(c.logger, extractors...)
+	plain := make([]ContextExtractor, 0, len(extractors))
+	for _, f := range extractors {
+		if f != nil {
+			plain = append(plain, f)
+		}
 	}
 
-	combined := make([]ContextExtractor, len(c.extractors)+len(extractors))
-	copy(combined, c.extractors)
-	copy(combined[len(c.extractors):], extractors)
+	// The copy and its extractor set share one allocation.
+	derived := &struct {
+		logger ContextLogger
+		set    extractorSet
+	}{logger: *c, set: extractorSet{parent: c.extractors, plain: plain}}
+	derived.logger.extractors = &derived.set
 
-	return &ContextLogger{
-		logger:     c.logger,
-		extractors: combined,
+	return &derived.logger
+}
+
+// Check returns a CheckedEntry if logging a message at lvl is enabled, and nil
+// otherwise. Fields extracted from ctx are prepended to the fields passed to
+// the entry's Write method.
+func (c *ContextLogger) Check(ctx context.Context, lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
+	return c.check(ctx, lvl, msg)
+}
+
+// Debug logs a message at DebugLevel with fields extracted from ctx.
+func (c *ContextLogger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.DebugLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Info logs a message at InfoLevel with fields extracted from ctx.
+func (c *ContextLogger) Info(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.InfoLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Warn logs a message at WarnLevel with fields extracted from ctx.
+func (c *ContextLogger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.WarnLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Error logs a message at ErrorLevel with fields extracted from ctx.
+func (c *ContextLogger) Error(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.ErrorLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// DPanic logs a message at DPanicLevel with fields extracted from ctx. A
+// development logger then panics.
+func (c *ContextLogger) DPanic(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.DPanicLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Panic logs a message at PanicLevel with fields extracted from ctx, then
+// panics.
+func (c *ContextLogger) Panic(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.PanicLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Fatal logs a message at FatalLevel with fields extracted from ctx, then
+// calls os.Exit(1).
+func (c *ContextLogger) Fatal(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := c.check(ctx, zap.FatalLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// check must be called directly by Check or a level method so that the
+// caller skip of the checker logger points at their caller. Extraction is
+// deferred to a pre-write hook and never runs for disabled levels.
+func (c *ContextLogger) check(ctx context.Context, lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
+	return c.deferExtraction(ctx, c.checker.Check(lvl, msg))
+}
+
+// deferExtraction adds a pre-write hook to ce that prepends the fields
+// extracted from ctx.
+func (c *ContextLogger) deferExtraction(ctx context.Context, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ce == nil || c.extractors.size() == 0 {
+		return ce
 	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return ce.Before(ce.Entry, func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		return ent, c.extract(ctx, fields)
+	})
 }
 
 // Logger returns the underlying zap logger.
@@ -95,8 +244,18 @@ func (c *ContextLogger) Logger() *zap.Logger {
 	return c.logger
 }
 
+// LogFielder is implemented by context values that describe themselves as
+// zap fields. Extractors add the returned fields inline instead of encoding
+// the value, so the type decides which of its members are logged.
+type LogFielder interface {
+	LogFields() []zap.Field
+}
+
 // WithValueExtractor extracts non-nil context values using each key's string
-// representation as the zap field name.
+// representation as the zap field name. Values implementing LogFielder add
+// their fields inline, values implementing zapcore.ObjectMarshaler or
+// zapcore.ArrayMarshaler are logged as nested objects or arrays, and other
+// values are logged with zap.Any.
 func WithValueExtractor[T interface {
 	comparable
 	fmt.Stringer
@@ -111,15 +270,30 @@ func WithValueExtractor[T interface {
 		fields := make([]zap.Field, 0, len(keys))
 
 		for _, k := range keys {
-			if val := ctx.Value(k); val != nil {
-				fields = append(fields, zap.Any(k.String(), val))
-			}
+			fields = appendValue(fields, k.String(), ctx.Value(k))
 		}
 
 		return fields
 	}
 }
 
+// appendValue appends the fields describing val under name, skipping nil
+// values.
+func appendValue(fields []zap.Field, name string, val any) []zap.Field {
+	switch v := val.(type) {
+	case nil:
+		return fields
+	case LogFielder:
+		return append(fields, v.LogFields()...)
+	case zapcore.ObjectMarshaler:
+		return append(fields, zap.Object(name, v))
+	case zapcore.ArrayMarshaler:
+		return append(fields, zap.Array(name, v))
+	default:
+		return append(fields, zap.Any(name, v))
+	}
+}
+
 // WithContextCarrier exposes ctx to custom zap cores under fieldName.
 // Standard zap encoders skip the carrier field.
 func WithContextCarrier(fieldName string) ContextExtractor {
@@ -128,13 +302,17 @@ func WithContextCarrier(fieldName string) ContextExtractor {
 			return nil
 		}
 
-		return []zap.Field{
-			{
-				Key:       fieldName,
-				Type:      zapcore.SkipType,
-				Interface: ctx,
-			},
-		}
+		return []zap.Field{ContextField(fieldName, ctx)}
+	}
+}
+
+// ContextField returns a carrier field holding ctx under fieldName, as added by
+// WithContextCarrier. Standard zap encoders skip it.
+func ContextField(fieldName string, ctx context.Context) zap.Field {
+	return zap.Field{
+		Key:       fieldName,
+		Type:      zapcore.SkipType,
+		Interface: ctx,
 	}
 }
 
@@ -169,3 +347,10 @@ func WithDeadlineExtractor() ContextExtractor {
 		return fields
 	}
 }
+
+var deadlineSpecs = []FieldSpec{
+	{Key: FieldContextDeadlineAt, Type: TypeTime},
+	{Key: FieldContextTimeLeft, Type: TypeDuration},
+	{Key: FieldContextError, Type: TypeString},
+	{Key: FieldContextCause, Type: TypeString},
+}
@@ -2,10 +2,12 @@ package contextlogger
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type benchmarkContextKey string
@@ -86,3 +88,75 @@ func BenchmarkContextLoggerWith(b *testing.B) {
 		_ = base.With(WithValueExtractor(requestIDKey), WithDeadlineExtractor())
 	}
 }
+
+func BenchmarkContextLoggerCtxDisabledLevel(b *testing.B) {
+	userIDKey := benchmarkContextKey("user_id")
+	requestIDKey := benchmarkContextKey("request_id")
+
+	ctx := context.WithValue(context.Background(), userIDKey, "user-123")
+	ctx = context.WithValue(ctx, requestIDKey, "req-456")
+
+	logger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		zapcore.InfoLevel,
+	))
+	cl := WithContext(logger, WithValueExtractor(userIDKey, requestIDKey), WithDeadlineExtractor())
+
+	b.ReportAllocs()
+	for b.Loop() {
+		cl.Ctx(ctx).Debug("dropped")
+	}
+}
+
+func BenchmarkContextLoggerCtxEnabledLevel(b *testing.B) {
+	userIDKey := benchmarkContextKey("user_id")
+	ctx := context.WithValue(context.Background(), userIDKey, "user-123")
+
+	logger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		zapcore.InfoLevel,
+	))
+	cl := WithContext(logger, WithValueExtractor(userIDKey))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		cl.Ctx(ctx).Info("written")
+	}
+}
+
+func BenchmarkContextLoggerLevelMethods(b *testing.B) {
+	userIDKey := benchmarkContextKey("user_id")
+	ctx := context.WithValue(context.Background(), userIDKey, "user-123")
+
+	logger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		zapcore.InfoLevel,
+	))
+	cl := WithContext(logger, WithValueExtractor(userIDKey), WithDeadlineExtractor())
+
+	b.Run("disabled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			cl.Debug(ctx, "dropped", zap.Int("status", 200))
+		}
+	})
+
+	b.Run("enabled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			cl.Info(ctx, "written", zap.Int("status", 200))
+		}
+	})
+
+	b.Run("enabled_attached", func(b *testing.B) {
+		attached := cl.Attach(ctx)
+
+		b.ReportAllocs()
+		for b.Loop() {
+			cl.Info(attached, "written", zap.Int("status", 200))
+		}
+	})
+}
@@ -3,12 +3,15 @@ package contextlogger
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 )
 
@@ -467,9 +470,9 @@ func TestContextLogger_CtxIsSideEffectFree(t *testing.T) {
 	ctx = context.WithValue(ctx, key1, "user-1")
 	ctx = context.WithValue(ctx, key2, "req-1")
 
-	// Snapshot extractor slice header before repeated calls.
-	extractorsBefore := cl.extractors
-	lenBefore := len(extractorsBefore)
+	// Snapshot the extractor entries before repeated calls.
+	entriesBefore := cl.extractors.entries.Load()
+	lenBefore := len(*entriesBefore)
 	baseLogger := cl.Logger()
 
 	for range 50 {
@@ -483,10 +486,10 @@ func TestContextLogger_CtxIsSideEffectFree(t *testing.T) {
 		require.NotNil(t, fields["context_deadline_at"])
 	}
 
-	// Receiver unchanged: same underlying logger and same extractor slice header.
+	// Receiver unchanged: same underlying logger and same extractor entries.
 	require.Same(t, baseLogger, cl.Logger())
-	require.Equal(t, lenBefore, len(cl.extractors))
-	require.Equal(t, fmt.Sprintf("%p", extractorsBefore), fmt.Sprintf("%p", cl.extractors))
+	require.Equal(t, lenBefore, len(*cl.extractors.entries.Load()))
+	require.Same(t, entriesBefore, cl.extractors.entries.Load())
 }
 
 func TestContextLogger_CtxConcurrent(t *testing.T) {
@@ -534,17 +537,20 @@ func TestContextLogger_WithMethodCombinedNonMutation(t *testing.T) {
 	key2 := contextKeyString("request_id")
 
 	parent := WithContext(logger, WithValueExtractor(key1))
-	parentLenBefore := len(parent.extractors)
-	parentFirst := parent.extractors[0]
+	parentEntries := parent.extractors.entries.Load()
+	parentLenBefore := len(*parentEntries)
+	parentFirst := (*parentEntries)[0]
 
 	child := parent.With(WithValueExtractor(key2))
 
-	// Parent's slice must not have been extended or mutated.
-	require.Equal(t, parentLenBefore, len(parent.extractors))
-	require.NotSame(t, &parent.extractors[0], &child.extractors[0])
+	// Parent's entries must not have been extended or mutated; the child only
+	// adds its own on top of them.
+	require.Equal(t, parentLenBefore, len(*parent.extractors.entries.Load()))
+	require.Same(t, parent.extractors, child.extractors.parent)
+	require.NotSame(t, parentFirst, child.extractors.load()[0])
 
-	// Mutating the child's extractor slice must not affect the parent.
-	child.extractors[0] = nil
+	// Replacing the child's extractors must not affect the parent.
+	child.SetExtractors()
 
 	observed.TakeAll()
 	ctx := context.WithValue(context.Background(), key1, "user-parent")
@@ -554,11 +560,7 @@ func TestContextLogger_WithMethodCombinedNonMutation(t *testing.T) {
 	fields := entries[0].ContextMap()
 	require.Equal(t, "user-parent", fields[key1.String()])
 	// Parent's first extractor must still be intact.
-	require.NotNil(t, parent.extractors[0])
-	require.Equal(t,
-		fmt.Sprintf("%p", parentFirst),
-		fmt.Sprintf("%p", parent.extractors[0]),
-	)
+	require.Same(t, parentFirst, (*parent.extractors.entries.Load())[0])
 }
 
 func TestContextLogger_WithValueExtractor_KeySliceCopied(t *testing.T) {
@@ -597,3 +599,144 @@ func TestContextLogger_Logger(t *testing.T) {
 		require.Same(t, logger, returned)
 	})
 }
+
+func TestContextLogger_LevelMethods(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+
+	t.Run("writes extracted and call-site fields in one entry", func(t *testing.T) {
+		core, observed := observer.New(zap.DebugLevel)
+		cl := New(zap.New(core), WithValueExtractor(key))
+
+		logs := []struct {
+			level zapcore.Level
+			log   func(context.Context, string, ...zap.Field)
+		}{
+			{zap.DebugLevel, cl.Debug},
+			{zap.InfoLevel, cl.Info},
+			{zap.WarnLevel, cl.Warn},
+			{zap.ErrorLevel, cl.Error},
+			{zap.DPanicLevel, cl.DPanic},
+		}
+
+		for _, l := range logs {
+			l.log(ctx, "level-method", zap.Int("status", 200))
+
+			entries := observed.TakeAll()
+			require.Len(t, entries, 1)
+			require.Equal(t, l.level, entries[0].Level)
+			require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+			require.Equal(t, int64(200), entries[0].ContextMap()["status"])
+			require.Equal(t, key.String(), entries[0].Context[0].Key)
+		}
+	})
+
+	t.Run("skips extractors for disabled levels", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		var calls atomic.Int32
+		cl := New(logger, countingExtractor(&calls))
+
+		cl.Debug(ctx, "dropped")
+
+		require.Zero(t, calls.Load())
+		require.Empty(t, observed.TakeAll())
+	})
+
+	t.Run("reports the caller of the level method", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		cl := New(zap.New(core, zap.AddCaller()), WithValueExtractor(key))
+
+		cl.Info(ctx, "caller")
+		ce := cl.Check(ctx, zap.InfoLevel, "checked")
+		require.NotNil(t, ce)
+		ce.Write()
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			require.True(t, entry.Caller.Defined)
+			require.Equal(t, "logger_test.go", filepath.Base(entry.Caller.File))
+		}
+	})
+
+	t.Run("check returns nil for disabled levels", func(t *testing.T) {
+		logger, _ := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		require.Nil(t, cl.Check(ctx, zap.DebugLevel, "disabled"))
+	})
+
+	t.Run("handles nil context", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		cl.Info(nil, "nil-context")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		_, ok := entries[0].ContextMap()[key.String()]
+		require.False(t, ok)
+	})
+
+	t.Run("panics after writing", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		require.Panics(t, func() { cl.Panic(ctx, "panic") })
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+	})
+
+	t.Run("runs fatal hook after writing", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		cl := New(zap.New(core, zap.WithFatalHook(zapcore.WriteThenPanic)), WithValueExtractor(key))
+
+		require.Panics(t, func() { cl.Fatal(ctx, "fatal") })
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, zap.FatalLevel, entries[0].Level)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+	})
+}
+
+type testTenant struct {
+	id     string
+	apiKey string
+}
+
+func (t testTenant) LogFields() []zap.Field {
+	return []zap.Field{zap.String("tenant_id", t.id), zap.Bool("tenant_has_key", t.apiKey != "")}
+}
+
+func TestContextLogger_WithValueExtractor_SelfDescribing(t *testing.T) {
+	logger, observed := newTestLogger()
+
+	principalKey := contextKeyString("principal")
+	rolesKey := contextKeyString("roles")
+	tenantKey := contextKeyString("tenant")
+	cl := WithContext(logger, WithValueExtractor(principalKey, rolesKey, tenantKey))
+
+	ctx := context.WithValue(context.Background(), principalKey, testPrincipal{id: "p-1"})
+	ctx = context.WithValue(ctx, rolesKey, testRoles{"admin", "billing"})
+	ctx = context.WithValue(ctx, tenantKey, testTenant{id: "t-1", apiKey: "secret"})
+
+	observed.TakeAll()
+	cl.Ctx(ctx).Info("self-describing")
+	entries := observed.TakeAll()
+	require.Len(t, entries, 1)
+
+	logged := entries[0].Context
+	require.Equal(t, zapcore.ObjectMarshalerType, logged[1].Type)
+	require.Equal(t, zapcore.ArrayMarshalerType, logged[2].Type)
+
+	fields := entries[0].ContextMap()
+	require.Equal(t, map[string]interface{}{"id": "p-1"}, fields["principal"])
+	require.Equal(t, []interface{}{"admin", "billing"}, fields["roles"])
+	require.Equal(t, "t-1", fields["tenant_id"])
+	require.Equal(t, true, fields["tenant_has_key"])
+	_, ok := fields["tenant"]
+	require.False(t, ok)
+}
@@ -0,0 +1,312 @@
+package contextlogger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// errValueLimit stops a marshaler once the measured value exceeds a limit.
+var errValueLimit = errors.New("contextlogger: value limit exceeded")
+
+// measurer estimates the JSON size and nesting depth of a field. Objects and
+// arrays are measured as they marshal themselves, which stops as soon as a
+// limit is exceeded; strings are counted without escaping, so the size is
+// approximate. Values logged with zap.Any are encoded with encoding/json, as
+// the JSON encoder does, and measured from the result.
+type measurer struct {
+	maxBytes int
+	maxDepth int
+	size     int
+	depth    int
+	exceeded bool
+	scratch  [64]byte
+}
+
+// exceeds reports whether field is larger than maxBytes or nested deeper than
+// maxDepth. A non-positive limit is not enforced.
+func exceeds(field zap.Field, maxBytes, maxDepth int) bool {
+	m := measurer{maxBytes: maxBytes, maxDepth: maxDepth}
+	field.AddTo(&m)
+
+	return m.exceeded
+}
+
+func (m *measurer) add(n int) {
+	if m.exceeded {
+		return
+	}
+
+	m.size += n
+	if m.maxBytes > 0 && m.size > m.maxBytes {
+		m.exceeded = true
+	}
+}
+
+// key counts an object key with its quotes and colon; the value counts the
+// separating comma.
+func (m *measurer) key(key string) {
+	m.add(len(key) + 3)
+}
+
+// nest measures one level of nesting, stopping before it if the depth limit
+// is reached.
+func (m *measurer) nest(measure func() error) error {
+	if m.exceeded {
+		return errValueLimit
+	}
+
+	if m.maxDepth > 0 && m.depth >= m.maxDepth {
+		m.exceeded = true
+		return errValueLimit
+	}
+
+	m.depth++
+	m.add(2)
+	err := measure()
+	m.depth--
+
+	if m.exceeded {
+		return errValueLimit
+	}
+
+	return err
+}
+
+func (m *measurer) number(b []byte) {
+	m.add(len(b))
+}
+
+// reflected measures the encoding/json form of value. Values that fail to
+// encode, such as cyclic ones, exceed the limits.
+func (m *measurer) reflected(value interface{}) error {
+	if m.exceeded {
+		return errValueLimit
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		m.exceeded = true
+		return errValueLimit
+	}
+
+	if m.maxDepth > 0 && m.depth+jsonDepth(encoded) > m.maxDepth {
+		m.exceeded = true
+		return errValueLimit
+	}
+
+	m.add(len(encoded) + 1)
+
+	if m.exceeded {
+		return errValueLimit
+	}
+
+	return nil
+}
+
+// jsonDepth returns how deeply objects and arrays nest in encoded JSON.
+func jsonDepth(encoded []byte) int {
+	depth, deepest := 0, 0
+	inString, escaped := false, false
+
+	for _, b := range encoded {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			escaped = b == '\\'
+			inString = b != '"'
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+			deepest = max(deepest, depth)
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+
+	return deepest
+}
+
+func (m *measurer) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	m.key(key)
+	return m.AppendArray(arr)
+}
+
+func (m *measurer) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	m.key(key)
+	return m.AppendObject(obj)
+}
+
+func (m *measurer) AddBinary(key string, value []byte) {
+	m.key(key)
+	m.add(base64.StdEncoding.EncodedLen(len(value)) + 2)
+}
+
+func (m *measurer) AddByteString(key string, value []byte) {
+	m.key(key)
+	m.AppendByteString(value)
+}
+
+func (m *measurer) AddBool(key string, value bool) {
+	m.key(key)
+	m.AppendBool(value)
+}
+
+func (m *measurer) AddComplex128(key string, value complex128) {
+	m.key(key)
+	m.AppendComplex128(value)
+}
+
+func (m *measurer) AddComplex64(key string, value complex64) {
+	m.key(key)
+	m.AppendComplex64(value)
+}
+
+func (m *measurer) AddDuration(key string, value time.Duration) {
+	m.key(key)
+	m.AppendDuration(value)
+}
+
+func (m *measurer) AddFloat64(key string, value float64) {
+	m.key(key)
+	m.AppendFloat64(value)
+}
+
+func (m *measurer) AddFloat32(key string, value float32) {
+	m.key(key)
+	m.AppendFloat32(value)
+}
+
+func (m *measurer) AddInt(key string, value int) { m.AddInt64(key, int64(value)) }
+
+func (m *measurer) AddInt64(key string, value int64) {
+	m.key(key)
+	m.AppendInt64(value)
+}
+
+func (m *measurer) AddInt32(key string, value int32) { m.AddInt64(key, int64(value)) }
+
+func (m *measurer) AddInt16(key string, value int16) { m.AddInt64(key, int64(value)) }
+
+func (m *measurer) AddInt8(key string, value int8) { m.AddInt64(key, int64(value)) }
+
+func (m *measurer) AddString(key, value string) {
+	m.key(key)
+	m.AppendString(value)
+}
+
+func (m *measurer) AddTime(key string, value time.Time) {
+	m.key(key)
+	m.AppendTime(value)
+}
+
+func (m *measurer) AddUint(key string, value uint) { m.AddUint64(key, uint64(value)) }
+
+func (m *measurer) AddUint64(key string, value uint64) {
+	m.key(key)
+	m.AppendUint64(value)
+}
+
+func (m *measurer) AddUint32(key string, value uint32) { m.AddUint64(key, uint64(value)) }
+
+func (m *measurer) AddUint16(key string, value uint16) { m.AddUint64(key, uint64(value)) }
+
+func (m *measurer) AddUint8(key string, value uint8) { m.AddUint64(key, uint64(value)) }
+
+func (m *measurer) AddUintptr(key string, value uintptr) { m.AddUint64(key, uint64(value)) }
+
+func (m *measurer) AddReflected(key string, value interface{}) error {
+	m.key(key)
+	return m.reflected(value)
+}
+
+// OpenNamespace counts the namespace as an open object; the JSON encoder
+// closes it at the end of the entry.
+func (m *measurer) OpenNamespace(key string) {
+	m.key(key)
+	m.add(2)
+}
+
+func (m *measurer) AppendArray(arr zapcore.ArrayMarshaler) error {
+	return m.nest(func() error { return arr.MarshalLogArray(m) })
+}
+
+func (m *measurer) AppendObject(obj zapcore.ObjectMarshaler) error {
+	return m.nest(func() error { return obj.MarshalLogObject(m) })
+}
+
+func (m *measurer) AppendReflected(value interface{}) error {
+	return m.reflected(value)
+}
+
+func (m *measurer) AppendBool(value bool) {
+	m.number(strconv.AppendBool(m.scratch[:0], value))
+	m.add(1)
+}
+
+func (m *measurer) AppendByteString(value []byte) {
+	m.add(len(value) + 3)
+}
+
+func (m *measurer) AppendComplex128(value complex128) {
+	m.number(strconv.AppendFloat(m.scratch[:0], real(value), 'f', -1, 64))
+	m.number(strconv.AppendFloat(m.scratch[:0], imag(value), 'f', -1, 64))
+	m.add(5)
+}
+
+func (m *measurer) AppendComplex64(value complex64) { m.AppendComplex128(complex128(value)) }
+
+// AppendDuration counts nanoseconds, the JSON encoder's default.
+func (m *measurer) AppendDuration(value time.Duration) { m.AppendInt64(int64(value)) }
+
+func (m *measurer) AppendFloat64(value float64) {
+	m.number(strconv.AppendFloat(m.scratch[:0], value, 'f', -1, 64))
+	m.add(1)
+}
+
+func (m *measurer) AppendFloat32(value float32) {
+	m.number(strconv.AppendFloat(m.scratch[:0], float64(value), 'f', -1, 32))
+	m.add(1)
+}
+
+func (m *measurer) AppendInt(value int) { m.AppendInt64(int64(value)) }
+
+func (m *measurer) AppendInt64(value int64) {
+	m.number(strconv.AppendInt(m.scratch[:0], value, 10))
+	m.add(1)
+}
+
+func (m *measurer) AppendInt32(value int32) { m.AppendInt64(int64(value)) }
+
+func (m *measurer) AppendInt16(value int16) { m.AppendInt64(int64(value)) }
+
+func (m *measurer) AppendInt8(value int8) { m.AppendInt64(int64(value)) }
+
+func (m *measurer) AppendString(value string) {
+	m.add(len(value) + 3)
+}
+
+// AppendTime counts nanoseconds since the epoch, the JSON encoder's default.
+func (m *measurer) AppendTime(value time.Time) { m.AppendInt64(value.UnixNano()) }
+
+func (m *measurer) AppendUint(value uint) { m.AppendUint64(uint64(value)) }
+
+func (m *measurer) AppendUint64(value uint64) {
+	m.number(strconv.AppendUint(m.scratch[:0], value, 10))
+	m.add(1)
+}
+
+func (m *measurer) AppendUint32(value uint32) { m.AppendUint64(uint64(value)) }
+
+func (m *measurer) AppendUint16(value uint16) { m.AppendUint64(uint64(value)) }
+
+func (m *measurer) AppendUint8(value uint8) { m.AppendUint64(uint64(value)) }
+
+func (m *measurer) AppendUintptr(value uintptr) { m.AppendUint64(uint64(value)) }
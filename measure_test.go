@@ -0,0 +1,82 @@
+package contextlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type measuredUser struct {
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags,omitempty"`
+	Secret  string            `json:"-"`
+	Created time.Time         `json:"created"`
+	Labels  map[string]string `json:"labels"`
+	hidden  string
+}
+
+type cyclic struct {
+	Next *cyclic
+}
+
+func TestMeasurer(t *testing.T) {
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+
+	t.Run("estimates the JSON size", func(t *testing.T) {
+		fields := []zap.Field{
+			zap.String("s", strings.Repeat("x", 200)),
+			zap.Ints("ints", []int{1, 22, 333, 4444}),
+			zap.Any("user", measuredUser{
+				Name:    "ana",
+				Tags:    []string{"a", "b"},
+				Created: time.Unix(0, 0).UTC(),
+				Labels:  map[string]string{"team": "core"},
+				hidden:  "h",
+			}),
+			zap.Error(errors.New("boom")),
+			zap.Binary("raw", []byte("abcdef")),
+			zap.Object("obj", fieldsObject{zap.Int("n", 1), zap.Bool("ok", true), zap.Duration("d", time.Second)}),
+		}
+
+		for _, field := range fields {
+			buf, err := encoder.EncodeEntry(zapcore.Entry{}, []zap.Field{field})
+			require.NoError(t, err)
+			encoded := buf.Len() - len("{}\n")
+			buf.Free()
+
+			m := measurer{}
+			field.AddTo(&m)
+
+			require.InDelta(t, encoded, m.size, float64(encoded)/10+2, field.Key)
+		}
+	})
+
+	t.Run("exceeds limits with values that fail to encode", func(t *testing.T) {
+		loop := &cyclic{}
+		loop.Next = loop
+
+		require.True(t, exceeds(zap.Any("loop", loop), 0, 0))
+		require.True(t, exceeds(zap.Any("ch", make(chan int)), 0, 0))
+	})
+
+	t.Run("ignores brackets in strings when measuring depth", func(t *testing.T) {
+		field := zap.Any("labels", map[string]string{"a": `[[{"\"[`})
+
+		require.False(t, exceeds(field, 0, 1))
+		require.True(t, exceeds(zap.Any("nested", []any{[]int{1}}), 0, 1))
+	})
+
+	t.Run("enforces only positive limits", func(t *testing.T) {
+		field := zap.Any("nested", map[string]any{"a": []int{1}})
+
+		require.False(t, exceeds(field, 0, 0))
+		require.False(t, exceeds(field, 100, 2))
+		require.True(t, exceeds(field, 10, 0))
+		require.True(t, exceeds(field, 0, 1))
+	})
+}
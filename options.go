@@ -0,0 +1,39 @@
+package contextlogger
+
+import "go.uber.org/zap"
+
+// Option configures how a ContextLogger processes extracted fields.
+type Option func(*options)
+
+// options holds the settings applied to extracted fields.
+type options struct {
+	namespace  string
+	names      map[string]string
+	converters map[string]func(zap.Field) zap.Field
+	limits     Limits
+	duplicates DuplicatePolicy
+}
+
+// GroupFields nests every extracted field under an object named name, like
+// wrapping all extractors in Namespace. Carrier fields and reports of
+// panicking extractors stay at the top level. An empty name turns grouping
+// off.
+func GroupFields(name string) Option {
+	return func(o *options) {
+		o.namespace = name
+	}
+}
+
+// WithOptions returns a copy of the ContextLogger with opts applied. The copy
+// shares the underlying logger and extractors with the receiver.
+func (c *ContextLogger) WithOptions(opts ...Option) *ContextLogger {
+	clone := *c
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&clone.opts)
+		}
+	}
+
+	return &clone
+}
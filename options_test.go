@@ -0,0 +1,57 @@
+package contextlogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContextLogger_WithOptions(t *testing.T) {
+	key := contextKeyString("trace_id")
+	ctx := context.WithValue(context.Background(), key, "abc")
+
+	t.Run("leaves receiver unchanged", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		parent := New(logger, WithValueExtractor(key))
+		child := parent.WithOptions(nil, RenameFields(map[string]string{"trace_id": "trace"}))
+
+		require.NotSame(t, parent, child)
+		require.Equal(t, "abc", logAndAssert(t, ctx, observed, parent, "parent")["trace_id"])
+		require.Equal(t, "abc", logAndAssert(t, ctx, observed, child, "child")["trace"])
+	})
+
+	t.Run("With keeps options", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger).
+			WithOptions(RenameFields(map[string]string{"trace_id": "trace"})).
+			With(WithValueExtractor(key))
+
+		fields := logAndAssert(t, ctx, observed, cl, "with")
+		require.Equal(t, "abc", fields["trace"])
+		require.NotContains(t, fields, "trace_id")
+	})
+
+	t.Run("no options returns equivalent logger", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithFieldsExtractor()).WithOptions()
+
+		fields := logAndAssert(t, WithFields(ctx, zap.Int("n", 1)), observed, cl, "none")
+		require.Equal(t, int64(1), fields["n"])
+	})
+}
+
+func TestGroupFields(t *testing.T) {
+	logger, observed := newTestLogger()
+	key := contextKeyString("trace_id")
+	ctx := context.WithValue(context.Background(), key, "abc")
+	cl := New(logger, WithValueExtractor(key), WithContextCarrier("carrier")).WithOptions(GroupFields("ctx"))
+
+	fields := logAndAssert(t, ctx, observed, cl, "grouped")
+	require.Equal(t, map[string]interface{}{"trace_id": "abc"}, fields["ctx"])
+	require.NotContains(t, fields, "trace_id")
+
+	fields = logAndAssert(t, ctx, observed, cl.WithOptions(GroupFields("")), "ungrouped")
+	require.Equal(t, "abc", fields["trace_id"])
+}
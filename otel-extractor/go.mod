@@ -3,7 +3,7 @@ module github.com/adlandh/context-logger/otel-extractor
 go 1.25.0
 
 require (
-	github.com/adlandh/context-logger v1.6.6
+	github.com/adlandh/context-logger v1.7.0
 	github.com/stretchr/testify v1.12.0
 	go.opentelemetry.io/otel/trace v1.45.0
 	go.uber.org/zap v1.28.0
@@ -18,3 +18,8 @@ require (
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// The extractor registry this module registers with first ships in core
+// v1.7.0, which must be tagged before this module. Until then, and for
+// development in this repository, build against the core module here.
+replace github.com/adlandh/context-logger => ../
@@ -3,12 +3,17 @@ package otelextractor
 
 import (
 	"context"
+	"fmt"
 
 	ctxLogger "github.com/adlandh/context-logger"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// ExtractorName is the name With is registered under, with the fields it
+// adds, for contextlogger.NewFromConfig when this package is imported.
+const ExtractorName = "otel"
+
 const (
 	// FieldTraceID identifies the OpenTelemetry trace ID field.
 	FieldTraceID = "trace_id"
@@ -16,6 +21,21 @@ const (
 	FieldSpanID = "span_id"
 )
 
+var fieldSpecs = []ctxLogger.FieldSpec{
+	{Key: FieldTraceID, Type: ctxLogger.TypeString},
+	{Key: FieldSpanID, Type: ctxLogger.TypeString},
+}
+
+func init() {
+	ctxLogger.RegisterExtractor(ExtractorName, func(param string, _ map[string]string) (ctxLogger.ContextExtractor, error) {
+		if param != "" {
+			return nil, fmt.Errorf("unexpected parameter %q", param)
+		}
+
+		return With(), nil
+	}, fieldSpecs...)
+}
+
 // With returns an extractor for valid OpenTelemetry trace and span IDs.
 func With() ctxLogger.ContextExtractor {
 	return func(ctx context.Context) []zap.Field {
@@ -198,3 +198,35 @@ func TestOtelExtractor_CombinedWithOtherExtractors(t *testing.T) {
 		require.Equal(t, sc.SpanID().String(), fields[FieldSpanID])
 	})
 }
+
+func TestOtelExtractor_Registered(t *testing.T) {
+	logger, observed := newTestLogger()
+	cl, err := ctxLogger.NewFromConfig(logger, ctxLogger.Config{
+		Extractors: []ctxLogger.ExtractorConfig{{Name: ExtractorName}},
+	})
+	require.NoError(t, err)
+
+	sc := createSpanContext(
+		[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x01},
+		[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+	)
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+
+	fields := logAndAssert(t, ctx, observed, cl, "registered")
+	require.Equal(t, sc.TraceID().String(), fields[FieldTraceID])
+	require.Equal(t, sc.SpanID().String(), fields[FieldSpanID])
+
+	_, err = ctxLogger.NewExtractor(ExtractorName+":param", nil)
+	require.Error(t, err)
+}
+
+func TestOtelExtractor_Schema(t *testing.T) {
+	named, err := ctxLogger.NewNamedExtractor(ExtractorName, nil)
+	require.NoError(t, err)
+
+	schema := ctxLogger.New(nil).WithNamed(named).Schema()
+
+	require.Empty(t, schema.Undeclared)
+	require.Contains(t, schema.Fields, ctxLogger.FieldSpec{Key: FieldTraceID, Type: ctxLogger.TypeString})
+	require.Contains(t, schema.Fields, ctxLogger.FieldSpec{Key: FieldSpanID, Type: ctxLogger.TypeString})
+}
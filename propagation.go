@@ -0,0 +1,400 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TextMapCarrier stores propagated fields as string key-value pairs, such as
+// HTTP headers or message headers.
+type TextMapCarrier interface {
+	// Get returns the value stored under key, or an empty string.
+	Get(key string) string
+	// Set stores value under key, replacing any existing value.
+	Set(key, value string)
+	// Keys lists the keys stored in the carrier.
+	Keys() []string
+}
+
+// HeaderCarrier adapts http.Header to TextMapCarrier.
+type HeaderCarrier http.Header
+
+var _ TextMapCarrier = HeaderCarrier{}
+
+// Get returns the first value of the header key.
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+// Set sets the header key to value.
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// Keys lists the header names.
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// MapCarrier adapts map[string]string message headers, as used by Kafka, AMQP,
+// and NATS clients, to TextMapCarrier.
+type MapCarrier map[string]string
+
+var _ TextMapCarrier = MapCarrier{}
+
+// Get returns the value stored under key.
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set stores value under key.
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the stored keys.
+func (c MapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// propagatedValue is a context value propagated under name. Extract stores
+// the value returned by restore, and skips the value when restore is nil or
+// reports false.
+type propagatedValue struct {
+	name    string
+	key     any
+	restore func(string) (any, bool)
+}
+
+// Propagator copies selected context fields into a TextMapCarrier on the
+// sending side and restores them into the context on the receiving side, so
+// the receiving service's extractors log the same values.
+//
+// Each field is stored under the carrier key prefix+name, with underscores in
+// the name replaced by hyphens because some HTTP proxies drop header names
+// containing underscores. Values are percent-encoded.
+//
+// Extract restores only the values and fields listed with PropagateValues,
+// PropagateValue, and PropagateFields, at most DefaultMaxExtractedFields of
+// them, each cut to DefaultMaxExtractedValueBytes; see ExtractAllFields and
+// ExtractLimits.
+type Propagator struct {
+	prefix        string
+	values        []propagatedValue
+	fields        []string
+	allFields     bool
+	extractAll    bool
+	maxFields     int
+	maxValueBytes int
+}
+
+const (
+	// DefaultMaxExtractedFields is the default number of values and fields
+	// Extract restores from one carrier.
+	DefaultMaxExtractedFields = 32
+	// DefaultMaxExtractedValueBytes is the default size in bytes of a value
+	// restored by Extract; longer values are truncated.
+	DefaultMaxExtractedValueBytes = 256
+)
+
+// PropagatorOption configures a Propagator.
+type PropagatorOption func(*Propagator)
+
+// NewPropagator creates a Propagator that stores fields under carrier keys
+// starting with prefix, such as "X-Ctx-".
+func NewPropagator(prefix string, opts ...PropagatorOption) *Propagator {
+	p := &Propagator{
+		prefix:        prefix,
+		maxFields:     DefaultMaxExtractedFields,
+		maxValueBytes: DefaultMaxExtractedValueBytes,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+
+	return p
+}
+
+// PropagateValues propagates the context values stored under keys, named by
+// each key's string representation like WithValueExtractor. Values are sent
+// as strings and restored as string values under the same keys, so use it
+// only for keys holding strings and PropagateValue for other types. A Key
+// whose values are not strings is sent but not restored.
+func PropagateValues[T interface {
+	comparable
+	fmt.Stringer
+}](keys ...T) PropagatorOption {
+	values := make([]propagatedValue, 0, len(keys))
+	for _, k := range keys {
+		v := propagatedValue{name: k.String(), key: k, restore: restoreString}
+		if typed, ok := any(k).(typedKey); ok && typed.valueType() != reflect.TypeFor[string]() {
+			v.restore = nil
+		}
+
+		values = append(values, v)
+	}
+
+	return func(p *Propagator) {
+		p.values = append(p.values, values...)
+	}
+}
+
+// PropagateValue propagates the context value stored under key, named by the
+// key's string representation like PropagateValues, and restores it with
+// parse, so the receiving context holds the type the application stores
+// under key. Values parse rejects are not restored.
+func PropagateValue[K interface {
+	comparable
+	fmt.Stringer
+}, V any](key K, parse func(string) (V, error)) PropagatorOption {
+	v := propagatedValue{name: key.String(), key: key}
+	if parse != nil {
+		v.restore = func(s string) (any, bool) {
+			value, err := parse(s)
+			return value, err == nil
+		}
+	}
+
+	return func(p *Propagator) {
+		p.values = append(p.values, v)
+	}
+}
+
+func restoreString(s string) (any, bool) {
+	return s, true
+}
+
+// PropagateFields propagates the fields accumulated with WithFields under
+// names. Without names Inject sends every accumulated field, while Extract
+// still restores only listed names unless ExtractAllFields is set. Fields are
+// restored as string fields. Object, array, and carrier fields are not
+// propagated.
+func PropagateFields(names ...string) PropagatorOption {
+	names = append([]string(nil), names...)
+
+	return func(p *Propagator) {
+		if len(names) == 0 {
+			p.allFields = true
+			return
+		}
+
+		p.fields = append(p.fields, names...)
+	}
+}
+
+// ExtractAllFields makes Extract restore every carrier key starting with the
+// prefix as a field, named after the key lower-cased with hyphens replaced by
+// underscores. The sender then chooses the field names, so it can forge keys
+// such as msg or level; use it only for carriers from trusted services.
+func ExtractAllFields() PropagatorOption {
+	return func(p *Propagator) {
+		p.extractAll = true
+	}
+}
+
+// ExtractLimits sets the number of values and fields Extract restores from one
+// carrier and the size in bytes of each restored value. Carrier keys beyond
+// maxFields are ignored in key order, and longer values are truncated. A
+// non-positive limit is not enforced.
+func ExtractLimits(maxFields, maxValueBytes int) PropagatorOption {
+	return func(p *Propagator) {
+		p.maxFields = maxFields
+		p.maxValueBytes = maxValueBytes
+	}
+}
+
+// Inject stores the selected context values and accumulated fields of ctx in
+// carrier.
+func (p *Propagator) Inject(ctx context.Context, carrier TextMapCarrier) {
+	if ctx == nil || carrier == nil {
+		return
+	}
+
+	for _, v := range p.values {
+		val := ctx.Value(v.key)
+		if val == nil {
+			continue
+		}
+
+		if s, ok := fieldString(zap.Any(v.name, val)); ok {
+			carrier.Set(p.carrierKey(v.name), url.PathEscape(s))
+		}
+	}
+
+	for _, f := range contextFields(ctx) {
+		if !p.propagatesField(f.Key) {
+			continue
+		}
+
+		if s, ok := fieldString(f); ok {
+			carrier.Set(p.carrierKey(f.Key), url.PathEscape(s))
+		}
+	}
+}
+
+// Extract returns a copy of ctx with the values and fields found in carrier
+// restored, within the limits set with ExtractLimits. Carrier keys are matched
+// case-insensitively, and keys that name no listed value or field are ignored.
+// A nil context is treated as context.Background().
+func (p *Propagator) Extract(ctx context.Context, carrier TextMapCarrier) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if carrier == nil {
+		return ctx
+	}
+
+	keys := carrier.Keys()
+	sort.Strings(keys)
+
+	var (
+		fields   []zap.Field
+		restored int
+	)
+
+	for _, key := range keys {
+		if p.maxFields > 0 && restored >= p.maxFields {
+			break
+		}
+
+		if len(key) <= len(p.prefix) || !strings.EqualFold(key[:len(p.prefix)], p.prefix) {
+			continue
+		}
+
+		suffix := key[len(p.prefix):]
+		v, isValue := p.value(suffix)
+		name, isField := p.fieldName(suffix)
+
+		if !isValue && !isField {
+			continue
+		}
+
+		restored++
+		value := p.restoredValue(carrier.Get(key))
+
+		if isValue {
+			if v.restore == nil {
+				continue
+			}
+
+			if parsed, ok := v.restore(value); ok {
+				ctx = context.WithValue(ctx, v.key, parsed)
+			}
+
+			continue
+		}
+
+		fields = append(fields, zap.String(name, value))
+	}
+
+	return WithFields(ctx, fields...)
+}
+
+func (p *Propagator) carrierKey(name string) string {
+	return p.prefix + strings.ReplaceAll(name, "_", "-")
+}
+
+func (p *Propagator) propagatesField(name string) bool {
+	if p.allFields {
+		return true
+	}
+
+	for _, field := range p.fields {
+		if field == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// value returns the propagated value stored under the carrier key suffix.
+func (p *Propagator) value(suffix string) (propagatedValue, bool) {
+	for _, v := range p.values {
+		if carrierNameEqual(v.name, suffix) {
+			return v, true
+		}
+	}
+
+	return propagatedValue{}, false
+}
+
+// fieldName returns the accumulated field name stored under the carrier key
+// suffix.
+func (p *Propagator) fieldName(suffix string) (string, bool) {
+	for _, field := range p.fields {
+		if carrierNameEqual(field, suffix) {
+			return field, true
+		}
+	}
+
+	if p.extractAll {
+		return strings.ReplaceAll(strings.ToLower(suffix), "-", "_"), true
+	}
+
+	return "", false
+}
+
+// restoredValue unescapes a carrier value and cuts it to maxValueBytes.
+func (p *Propagator) restoredValue(value string) string {
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+
+	if p.maxValueBytes > 0 {
+		value = truncateBytes(value, p.maxValueBytes)
+	}
+
+	return value
+}
+
+// carrierNameEqual reports whether suffix is the carrier form of name.
+func carrierNameEqual(name, suffix string) bool {
+	return strings.EqualFold(strings.ReplaceAll(name, "_", "-"), suffix)
+}
+
+// fieldString returns the string form of a scalar field's value. It reports
+// false for fields without a scalar value, such as objects, arrays,
+// namespaces, and carrier fields.
+func fieldString(f zap.Field) (string, bool) {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String, true
+	case zapcore.StringerType:
+		return fmt.Sprint(f.Interface), true
+	case zapcore.ArrayMarshalerType, zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType,
+		zapcore.NamespaceType, zapcore.SkipType, zapcore.UnknownType:
+		return "", false
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+
+	value, ok := enc.Fields[f.Key]
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprint(value), true
+}
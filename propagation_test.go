@@ -0,0 +1,172 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPropagator(t *testing.T) {
+	requestIDKey := contextKeyString("request_id")
+	tenantKey := contextKeyString("tenant")
+
+	t.Run("round-trips values and fields through HTTP headers", func(t *testing.T) {
+		p := NewPropagator("X-Ctx-", PropagateValues(requestIDKey, tenantKey), PropagateFields("user_id"))
+
+		ctx := context.WithValue(context.Background(), requestIDKey, "req-7f3")
+		ctx = WithFields(ctx, zap.String("user_id", "u 42/é"), zap.String("secret", "s"))
+
+		header := http.Header{}
+		p.Inject(ctx, HeaderCarrier(header))
+
+		require.Equal(t, "req-7f3", header.Get("X-Ctx-Request-Id"))
+		require.Equal(t, "u%2042%2F%C3%A9", header.Get("X-Ctx-User-Id"))
+		require.Empty(t, header.Get("X-Ctx-Secret"))
+		require.Empty(t, header.Get("X-Ctx-Tenant"))
+
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(requestIDKey), WithFieldsExtractor())
+		received := p.Extract(context.Background(), HeaderCarrier(header))
+
+		fields := logAndAssert(t, received, observed, cl, "downstream")
+		require.Equal(t, "req-7f3", fields["request_id"])
+		require.Equal(t, "u 42/é", fields["user_id"])
+		_, ok := fields["secret"]
+		require.False(t, ok)
+	})
+
+	t.Run("round-trips all accumulated fields through message headers", func(t *testing.T) {
+		p := NewPropagator("ctx-", PropagateFields())
+
+		ctx := WithFields(context.Background(),
+			zap.String("tenant_id", "t-1"),
+			zap.Int("order_id", 7),
+			zap.Duration("budget", time.Second),
+			zap.Object("nested", fieldsObject{zap.String("a", "b")}),
+		)
+
+		headers := map[string]string{"other": "value"}
+		p.Inject(ctx, MapCarrier(headers))
+
+		require.Equal(t, map[string]string{
+			"other":         "value",
+			"ctx-tenant-id": "t-1",
+			"ctx-order-id":  "7",
+			"ctx-budget":    "1s",
+		}, headers)
+
+		received := NewPropagator("ctx-", ExtractAllFields()).Extract(context.Background(), MapCarrier(headers))
+		fields := WithFieldsExtractor()(received)
+
+		require.ElementsMatch(t, []zap.Field{
+			zap.String("tenant_id", "t-1"),
+			zap.String("order_id", "7"),
+			zap.String("budget", "1s"),
+		}, fields)
+	})
+
+	t.Run("ignores unlisted carrier keys", func(t *testing.T) {
+		p := NewPropagator("X-Ctx-", PropagateFields(), PropagateFields("tenant_id"))
+
+		received := p.Extract(context.Background(), MapCarrier{
+			"X-Ctx-Msg":       "forged",
+			"X-Ctx-Level":     "error",
+			"X-Ctx-Tenant-Id": "t-1",
+		})
+
+		require.Equal(t, []zap.Field{zap.String("tenant_id", "t-1")}, WithFieldsExtractor()(received))
+	})
+
+	t.Run("limits restored fields and values", func(t *testing.T) {
+		p := NewPropagator("X-Ctx-", ExtractAllFields(), ExtractLimits(2, 4))
+
+		received := p.Extract(context.Background(), MapCarrier{
+			"X-Ctx-A": "123456",
+			"X-Ctx-B": "b",
+			"X-Ctx-C": "c",
+		})
+
+		require.Equal(t, []zap.Field{zap.String("a", "1234"), zap.String("b", "b")}, WithFieldsExtractor()(received))
+	})
+
+	t.Run("applies default limits", func(t *testing.T) {
+		p := NewPropagator("X-Ctx-", ExtractAllFields())
+		carrier := MapCarrier{"X-Ctx-Big": strings.Repeat("x", DefaultMaxExtractedValueBytes+1)}
+		for i := range DefaultMaxExtractedFields {
+			carrier[fmt.Sprintf("X-Ctx-F%03d", i)] = "v"
+		}
+
+		fields := WithFieldsExtractor()(p.Extract(context.Background(), carrier))
+
+		require.Len(t, fields, DefaultMaxExtractedFields)
+		require.Equal(t, "big", fields[0].Key)
+		require.Len(t, fields[0].String, DefaultMaxExtractedValueBytes)
+	})
+
+	t.Run("matches carrier keys case-insensitively", func(t *testing.T) {
+		p := NewPropagator("X-Ctx-", PropagateValues(requestIDKey))
+
+		received := p.Extract(nil, MapCarrier{"x-ctx-REQUEST-ID": "req-1", "x-ctx-": "empty"})
+
+		require.Equal(t, "req-1", received.Value(requestIDKey))
+	})
+
+	t.Run("restores values with their key's type", func(t *testing.T) {
+		attemptKey := NewKey[int]("attempt")
+		regionKey := NewKey[string]("region")
+		p := NewPropagator("X-Ctx-", PropagateValue(attemptKey, strconv.Atoi), PropagateValues(regionKey))
+
+		carrier := MapCarrier{}
+		p.Inject(regionKey.Set(attemptKey.Set(context.Background(), 3), "eu"), carrier)
+		require.Equal(t, MapCarrier{"X-Ctx-attempt": "3", "X-Ctx-region": "eu"}, carrier)
+
+		received := p.Extract(context.Background(), carrier)
+
+		attempt, ok := attemptKey.Get(received)
+		require.True(t, ok)
+		require.Equal(t, 3, attempt)
+
+		region, ok := regionKey.Get(received)
+		require.True(t, ok)
+		require.Equal(t, "eu", region)
+	})
+
+	t.Run("skips values that do not parse", func(t *testing.T) {
+		attemptKey := NewKey[int]("attempt")
+		p := NewPropagator("X-Ctx-", PropagateValue(attemptKey, strconv.Atoi))
+
+		received := p.Extract(context.Background(), MapCarrier{"X-Ctx-Attempt": "third"})
+
+		require.Nil(t, received.Value(attemptKey))
+	})
+
+	t.Run("sends but does not restore non-string keys without a parser", func(t *testing.T) {
+		attemptKey := NewKey[int]("attempt")
+		p := NewPropagator("X-Ctx-", PropagateValues(attemptKey))
+
+		carrier := MapCarrier{}
+		p.Inject(attemptKey.Set(context.Background(), 3), carrier)
+		require.Equal(t, "3", carrier["X-Ctx-attempt"])
+
+		require.Nil(t, p.Extract(context.Background(), carrier).Value(attemptKey))
+	})
+
+	t.Run("handles nil context and carrier", func(t *testing.T) {
+		p := NewPropagator("X-Ctx-", nil, PropagateValues(requestIDKey))
+
+		require.NotPanics(t, func() {
+			p.Inject(nil, MapCarrier{})
+			p.Inject(context.Background(), nil)
+		})
+
+		ctx := context.Background()
+		require.Equal(t, ctx, p.Extract(ctx, nil))
+	})
+}
@@ -0,0 +1,62 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldContextExtractorError describes an extractor that panicked.
+const FieldContextExtractorError = "context_extractor_error"
+
+// extractorPanic describes a recovered extractor panic.
+type extractorPanic struct {
+	extractor string
+	value     any
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (p extractorPanic) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("extractor", p.extractor)
+	enc.AddString("panic", fmt.Sprint(p.value))
+
+	return nil
+}
+
+// callExtractor runs f against ctx. A panic is recovered and reported as a
+// context_extractor_error field naming the extractor name, or the function
+// implementing f when name is empty, so one faulty extractor cannot break
+// logging or the code that logs.
+func callExtractor(ctx context.Context, name string, f ContextExtractor) (fields []zap.Field, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if name == "" {
+				name = extractorName(f)
+			}
+
+			fields = []zap.Field{zap.Object(FieldContextExtractorError, extractorPanic{
+				extractor: name,
+				value:     r,
+			})}
+			panicked = true
+		}
+	}()
+
+	return f(ctx), false
+}
+
+// extractorName returns the name of the function implementing f, such as
+// "context-logger.WithDeadlineExtractor.func1".
+func extractorName(f ContextExtractor) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+
+	return path.Base(fn.Name())
+}
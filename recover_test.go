@@ -0,0 +1,72 @@
+package contextlogger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func panickingExtractor(context.Context) []zap.Field {
+	panic("boom")
+}
+
+func TestExtractorPanics(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+
+	assertReported := func(t *testing.T, report interface{}) {
+		t.Helper()
+
+		fields, ok := report.(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, "boom", fields["panic"])
+		require.Equal(t, "context-logger.panickingExtractor", fields["extractor"])
+	}
+
+	t.Run("reports panics and runs remaining extractors", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, panickingExtractor, WithValueExtractor(key))
+
+		require.NotPanics(t, func() {
+			cl.Info(ctx, "level method")
+			cl.Ctx(ctx).Info("bound")
+		})
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 2)
+
+		for _, entry := range entries {
+			fields := entry.ContextMap()
+			require.Equal(t, "req-1", fields["request_id"])
+			assertReported(t, fields[FieldContextExtractorError])
+		}
+	})
+
+	t.Run("reports panics inside namespaces at the top level", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, Namespace("ctx", WithValueExtractor(key), panickingExtractor))
+
+		fields := logAndAssert(t, ctx, observed, cl, "grouped")
+		require.Equal(t, map[string]interface{}{"request_id": "req-1"}, fields["ctx"])
+		assertReported(t, fields[FieldContextExtractorError])
+	})
+
+	t.Run("reports named extractors by name", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger).WithNamed(NamedExtractor{Name: "tenant", Extractor: panickingExtractor})
+
+		fields := logAndAssert(t, ctx, observed, cl, "named")
+		report, ok := fields[FieldContextExtractorError].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, "tenant", report["extractor"])
+	})
+
+	t.Run("names closures by their constructor", func(t *testing.T) {
+		name := extractorName(WithDeadlineExtractor())
+
+		require.True(t, strings.HasPrefix(name, "context-logger.WithDeadlineExtractor."), name)
+	})
+}
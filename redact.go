@@ -0,0 +1,350 @@
+package contextlogger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redactor replaces the string form of a sensitive value.
+type Redactor func(value string) string
+
+// RedactRule selects extracted fields by key and redacts their values.
+type RedactRule struct {
+	// Pattern matches field keys using path.Match syntax, such as "email" or
+	// "*_token". Malformed patterns match nothing.
+	Pattern string
+	// Redactor replaces the value of matching fields. A nil Redactor masks the
+	// whole value.
+	Redactor Redactor
+}
+
+// Redact wraps extractors so that the values of fields matching a rule are
+// redacted before they are logged. The first matching rule applies. Matching
+// fields are logged as strings; objects and arrays are redacted in their
+// encoded form. The members of objects, including Namespace groups, inline
+// objects, and objects in arrays, are matched by their own keys, while values
+// logged with zap.Any are only matched as a whole. Carrier fields are passed
+// through.
+func Redact(rules []RedactRule, extractors ...ContextExtractor) ContextExtractor {
+	rules = append([]RedactRule(nil), rules...)
+	extractors = append([]ContextExtractor(nil), extractors...)
+
+	return func(ctx context.Context) []zap.Field {
+		var fields []zap.Field
+
+		for _, f := range extractors {
+			if f == nil {
+				continue
+			}
+
+			extracted, _ := callExtractor(ctx, "", f)
+			fields = append(fields, extracted...)
+		}
+
+		return redactFields(fields, rules)
+	}
+}
+
+// MaskValue replaces every character of a value with an asterisk except the
+// last keep characters, so "4111111111111111" becomes "************1111" with
+// keep set to 4. Values no longer than keep are masked completely.
+func MaskValue(keep int) Redactor {
+	return func(value string) string {
+		n := utf8.RuneCountInString(value)
+		if keep <= 0 || n <= keep {
+			return strings.Repeat("*", n)
+		}
+
+		masked := n - keep
+		for i := range value {
+			if masked == 0 {
+				return strings.Repeat("*", n-keep) + value[i:]
+			}
+
+			masked--
+		}
+
+		return value
+	}
+}
+
+// TruncateValue keeps the first n characters of a value.
+func TruncateValue(n int) Redactor {
+	return func(value string) string {
+		if n <= 0 {
+			return ""
+		}
+
+		for i := range value {
+			if n == 0 {
+				return value[:i]
+			}
+
+			n--
+		}
+
+		return value
+	}
+}
+
+// HashValue replaces a value with the hex-encoded HMAC-SHA256 of the value
+// keyed with secret. Equal values produce equal hashes, so hashed fields still
+// correlate log entries, while the secret prevents reversing them by hashing
+// candidate values.
+func HashValue(secret []byte) Redactor {
+	secret = append([]byte(nil), secret...)
+
+	return func(value string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(value))
+
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
+// redactFields redacts matching fields in place, copying Namespace groups
+// before redacting their members. The members of other objects, including
+// inline ones, and the objects in arrays are redacted as they are encoded.
+func redactFields(fields []zap.Field, rules []RedactRule) []zap.Field {
+	for i, field := range fields {
+		if field.Type == zapcore.SkipType {
+			continue
+		}
+
+		if field.Type != zapcore.InlineMarshalerType {
+			if rule, ok := matchRule(field.Key, rules); ok {
+				fields[i] = redactField(field, rule)
+				continue
+			}
+		}
+
+		switch v := field.Interface.(type) {
+		case fieldsObject:
+			if field.Type == zapcore.ObjectMarshalerType {
+				fields[i].Interface = fieldsObject(redactFields(append([]zap.Field(nil), v...), rules))
+			}
+		case zapcore.ObjectMarshaler:
+			if field.Type == zapcore.ObjectMarshalerType || field.Type == zapcore.InlineMarshalerType {
+				fields[i].Interface = redactedObject{ObjectMarshaler: v, rules: rules}
+			}
+		case zapcore.ArrayMarshaler:
+			if field.Type == zapcore.ArrayMarshalerType {
+				fields[i].Interface = redactedArray{ArrayMarshaler: v, rules: rules}
+			}
+		}
+	}
+
+	return fields
+}
+
+func matchRule(key string, rules []RedactRule) (RedactRule, bool) {
+	for _, rule := range rules {
+		if matched, err := path.Match(rule.Pattern, key); err == nil && matched {
+			return rule, true
+		}
+	}
+
+	return RedactRule{}, false
+}
+
+func redactField(field zap.Field, rule RedactRule) zap.Field {
+	redactor := rule.Redactor
+	if redactor == nil {
+		redactor = MaskValue(0)
+	}
+
+	value, ok := fieldString(field)
+	if !ok {
+		value = encodedString(field)
+	}
+
+	return zap.String(field.Key, redactor(value))
+}
+
+// encodedString returns the encoded form of a field without a scalar value,
+// such as an object or array.
+func encodedString(field zap.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+
+	if value, ok := enc.Fields[field.Key]; ok {
+		return fmt.Sprint(value)
+	}
+
+	return fmt.Sprint(enc.Fields)
+}
+
+// redactedObject redacts the members of an object as it is encoded.
+type redactedObject struct {
+	zapcore.ObjectMarshaler
+	rules []RedactRule
+}
+
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(redactingEncoder{ObjectEncoder: enc, rules: o.rules})
+}
+
+// redactedArray redacts the members of the objects in an array as it is
+// encoded.
+type redactedArray struct {
+	zapcore.ArrayMarshaler
+	rules []RedactRule
+}
+
+func (a redactedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(redactingArrayEncoder{ArrayEncoder: enc, rules: a.rules})
+}
+
+// redactingEncoder redacts the members of an object whose keys match a rule.
+type redactingEncoder struct {
+	zapcore.ObjectEncoder
+	rules []RedactRule
+}
+
+// redacted adds field redacted and reports true if its key matches a rule.
+func (e redactingEncoder) redacted(field zap.Field) bool {
+	rule, ok := matchRule(field.Key, e.rules)
+	if ok {
+		redactField(field, rule).AddTo(e.ObjectEncoder)
+	}
+
+	return ok
+}
+
+func (e redactingEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	if e.redacted(zap.Array(key, arr)) {
+		return nil
+	}
+
+	return e.ObjectEncoder.AddArray(key, redactedArray{ArrayMarshaler: arr, rules: e.rules})
+}
+
+func (e redactingEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	if e.redacted(zap.Object(key, obj)) {
+		return nil
+	}
+
+	return e.ObjectEncoder.AddObject(key, redactedObject{ObjectMarshaler: obj, rules: e.rules})
+}
+
+func (e redactingEncoder) AddBinary(key string, value []byte) {
+	if !e.redacted(zap.Binary(key, value)) {
+		e.ObjectEncoder.AddBinary(key, value)
+	}
+}
+
+func (e redactingEncoder) AddByteString(key string, value []byte) {
+	if !e.redacted(zap.ByteString(key, value)) {
+		e.ObjectEncoder.AddByteString(key, value)
+	}
+}
+
+func (e redactingEncoder) AddBool(key string, value bool) {
+	if !e.redacted(zap.Bool(key, value)) {
+		e.ObjectEncoder.AddBool(key, value)
+	}
+}
+
+func (e redactingEncoder) AddComplex128(key string, value complex128) {
+	if !e.redacted(zap.Complex128(key, value)) {
+		e.ObjectEncoder.AddComplex128(key, value)
+	}
+}
+
+func (e redactingEncoder) AddComplex64(key string, value complex64) {
+	if !e.redacted(zap.Complex64(key, value)) {
+		e.ObjectEncoder.AddComplex64(key, value)
+	}
+}
+
+func (e redactingEncoder) AddDuration(key string, value time.Duration) {
+	if !e.redacted(zap.Duration(key, value)) {
+		e.ObjectEncoder.AddDuration(key, value)
+	}
+}
+
+func (e redactingEncoder) AddFloat64(key string, value float64) {
+	if !e.redacted(zap.Float64(key, value)) {
+		e.ObjectEncoder.AddFloat64(key, value)
+	}
+}
+
+func (e redactingEncoder) AddFloat32(key string, value float32) {
+	if !e.redacted(zap.Float32(key, value)) {
+		e.ObjectEncoder.AddFloat32(key, value)
+	}
+}
+
+func (e redactingEncoder) AddInt(key string, value int) { e.AddInt64(key, int64(value)) }
+
+func (e redactingEncoder) AddInt64(key string, value int64) {
+	if !e.redacted(zap.Int64(key, value)) {
+		e.ObjectEncoder.AddInt64(key, value)
+	}
+}
+
+func (e redactingEncoder) AddInt32(key string, value int32) { e.AddInt64(key, int64(value)) }
+
+func (e redactingEncoder) AddInt16(key string, value int16) { e.AddInt64(key, int64(value)) }
+
+func (e redactingEncoder) AddInt8(key string, value int8) { e.AddInt64(key, int64(value)) }
+
+func (e redactingEncoder) AddString(key, value string) {
+	if !e.redacted(zap.String(key, value)) {
+		e.ObjectEncoder.AddString(key, value)
+	}
+}
+
+func (e redactingEncoder) AddTime(key string, value time.Time) {
+	if !e.redacted(zap.Time(key, value)) {
+		e.ObjectEncoder.AddTime(key, value)
+	}
+}
+
+func (e redactingEncoder) AddUint(key string, value uint) { e.AddUint64(key, uint64(value)) }
+
+func (e redactingEncoder) AddUint64(key string, value uint64) {
+	if !e.redacted(zap.Uint64(key, value)) {
+		e.ObjectEncoder.AddUint64(key, value)
+	}
+}
+
+func (e redactingEncoder) AddUint32(key string, value uint32) { e.AddUint64(key, uint64(value)) }
+
+func (e redactingEncoder) AddUint16(key string, value uint16) { e.AddUint64(key, uint64(value)) }
+
+func (e redactingEncoder) AddUint8(key string, value uint8) { e.AddUint64(key, uint64(value)) }
+
+func (e redactingEncoder) AddUintptr(key string, value uintptr) { e.AddUint64(key, uint64(value)) }
+
+func (e redactingEncoder) AddReflected(key string, value interface{}) error {
+	if e.redacted(zap.Reflect(key, value)) {
+		return nil
+	}
+
+	return e.ObjectEncoder.AddReflected(key, value)
+}
+
+// redactingArrayEncoder redacts the members of the objects in an array.
+type redactingArrayEncoder struct {
+	zapcore.ArrayEncoder
+	rules []RedactRule
+}
+
+func (e redactingArrayEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactedArray{ArrayMarshaler: arr, rules: e.rules})
+}
+
+func (e redactingArrayEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactedObject{ObjectMarshaler: obj, rules: e.rules})
+}
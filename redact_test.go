@@ -0,0 +1,143 @@
+package contextlogger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type redactTestPrincipal struct {
+	Email string
+	Name  string
+}
+
+func (p redactTestPrincipal) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("email", p.Email)
+	enc.AddString("name", p.Name)
+
+	return nil
+}
+
+type redactTestPrincipals []redactTestPrincipal
+
+func (p redactTestPrincipals) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, principal := range p {
+		if err := enc.AppendObject(principal); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestRedact(t *testing.T) {
+	emailKey := contextKeyString("email")
+	ctx := context.WithValue(context.Background(), emailKey, "jane@example.com")
+	ctx = WithFields(ctx,
+		zap.String("account_number", "4111111111111111"),
+		zap.String("api_token", "secret-token"),
+		zap.Int("order_id", 42),
+	)
+
+	secret := []byte("log-secret")
+
+	rules := []RedactRule{
+		{Pattern: "email", Redactor: HashValue(secret)},
+		{Pattern: "account_*", Redactor: MaskValue(4)},
+		{Pattern: "*_token"},
+	}
+
+	t.Run("redacts matching fields", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, Redact(rules, WithValueExtractor(emailKey), nil, WithFieldsExtractor()))
+
+		fields := logAndAssert(t, ctx, observed, cl, "redacted")
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("jane@example.com"))
+		require.Equal(t, hex.EncodeToString(mac.Sum(nil)), fields["email"])
+		require.Equal(t, "************1111", fields["account_number"])
+		require.Equal(t, "************", fields["api_token"])
+		require.Equal(t, int64(42), fields["order_id"])
+	})
+
+	t.Run("hashes deterministically", func(t *testing.T) {
+		extractor := Redact(rules, WithValueExtractor(emailKey))
+
+		require.Equal(t, extractor(ctx), extractor(ctx))
+		require.NotEqual(t,
+			extractor(ctx)[0].String,
+			Redact([]RedactRule{{Pattern: "email", Redactor: HashValue([]byte("other"))}}, WithValueExtractor(emailKey))(ctx)[0].String,
+		)
+	})
+
+	t.Run("redacts namespace members and encoded objects", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		objectCtx := WithFields(ctx, zap.Object("card", fieldsObject{zap.String("number", "4111")}))
+		cl := New(logger, Redact(
+			[]RedactRule{{Pattern: "account_number", Redactor: TruncateValue(4)}, {Pattern: "card"}},
+			Namespace("ctx", WithFieldsExtractor()),
+		))
+
+		fields := logAndAssert(t, objectCtx, observed, cl, "nested")
+		group, ok := fields["ctx"].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, "4111", group["account_number"])
+		require.Equal(t, "secret-token", group["api_token"])
+		require.IsType(t, "", group["card"])
+		require.NotContains(t, group["card"], "4111")
+
+		unredacted := logAndAssert(t, objectCtx, observed, New(logger, WithFieldsExtractor()), "plain")
+		require.Equal(t, "4111111111111111", unredacted["account_number"])
+	})
+
+	t.Run("redacts members of inline and marshaled objects", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		user := redactTestPrincipal{Email: "jane@example.com", Name: "Jane"}
+		objectCtx := WithFields(context.Background(),
+			zap.Inline(user),
+			zap.Object("owner", user),
+			zap.Array("members", redactTestPrincipals{user}),
+		)
+		cl := New(logger, Redact(rules, WithFieldsExtractor()))
+
+		fields := logAndAssert(t, objectCtx, observed, cl, "marshaled")
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("jane@example.com"))
+		hashed := hex.EncodeToString(mac.Sum(nil))
+
+		require.Equal(t, hashed, fields["email"])
+		require.Equal(t, "Jane", fields["name"])
+		require.Equal(t, map[string]interface{}{"email": hashed, "name": "Jane"}, fields["owner"])
+		require.Equal(t, []interface{}{map[string]interface{}{"email": hashed, "name": "Jane"}}, fields["members"])
+	})
+
+	t.Run("passes carrier fields and ignores malformed patterns", func(t *testing.T) {
+		fields := Redact(
+			[]RedactRule{{Pattern: "["}, {Pattern: "*"}},
+			WithContextCarrier("carrier"),
+		)(ctx)
+
+		require.Len(t, fields, 1)
+		require.Equal(t, zapcore.SkipType, fields[0].Type)
+		require.Nil(t, Redact(rules)(ctx))
+	})
+}
+
+func TestRedactors(t *testing.T) {
+	require.Equal(t, "******7890", MaskValue(4)("1234567890"))
+	require.Equal(t, "***ßü", MaskValue(2)("abcßü"))
+	require.Equal(t, "***", MaskValue(5)("abc"))
+	require.Equal(t, "***", MaskValue(0)("abc"))
+	require.Equal(t, "abcß", TruncateValue(4)("abcßü"))
+	require.Equal(t, "abc", TruncateValue(10)("abc"))
+	require.Empty(t, TruncateValue(0)("abc"))
+	require.Len(t, HashValue(nil)("abc"), sha256.Size*2)
+}
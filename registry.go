@@ -0,0 +1,200 @@
+package contextlogger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExtractorFactory builds an extractor from the parameter following the colon
+// in a registered name, such as request_id in "value:request_id", and from
+// extractor-specific options. The parameter is empty when the name has none.
+type ExtractorFactory func(param string, options map[string]string) (ContextExtractor, error)
+
+// registration is a registered extractor factory and the fields the
+// extractors it builds declare, given the parameter of their name.
+type registration struct {
+	factory ExtractorFactory
+	fields  func(param string) []FieldSpec
+}
+
+var (
+	registryMu sync.RWMutex
+	factories  = map[string]registration{
+		"deadline": {noParam(WithDeadlineExtractor), declared(deadlineSpecs...)},
+		"fields":   {factory: noParam(WithFieldsExtractor)},
+		"bag":      {factory: noParam(WithBagExtractor)},
+		"carrier":  {newCarrierExtractor, declared()},
+		"value":    {newValueExtractor, valueFields},
+	}
+	valueKeys = map[string]any{}
+)
+
+// RegisterExtractor makes an extractor factory available under name to
+// NewExtractor and NewFromConfig. Register extractors from other packages at
+// program start-up or in an init function, as the OpenTelemetry and Sentry
+// integrations do when imported. The fields the extractors may add are
+// declared for Schema; without any, Schema lists them as undeclared. It
+// panics if name is empty, contains a colon, or is already registered, or if
+// factory is nil.
+func RegisterExtractor(name string, factory ExtractorFactory, fields ...FieldSpec) {
+	if name == "" || strings.Contains(name, ":") {
+		panic(fmt.Sprintf("contextlogger: invalid extractor name %q", name))
+	}
+
+	if factory == nil {
+		panic("contextlogger: RegisterExtractor factory is nil for " + name)
+	}
+
+	r := registration{factory: factory}
+	if len(fields) > 0 {
+		r.fields = declared(fields...)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := factories[name]; dup {
+		panic("contextlogger: RegisterExtractor called twice for " + name)
+	}
+
+	factories[name] = r
+}
+
+// RegisterValue makes the context value stored under key available to the
+// built-in "value" extractor as "value:name", logged under name. Context keys
+// are usually unexported, so the package that owns a key registers it. Like
+// RegisterExtractor, it panics if name is empty or already registered.
+func RegisterValue(name string, key any) {
+	if name == "" {
+		panic("contextlogger: RegisterValue name is empty")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := valueKeys[name]; dup {
+		panic("contextlogger: RegisterValue called twice for " + name)
+	}
+
+	valueKeys[name] = key
+}
+
+// Extractors lists the registered extractor names in sorted order.
+func Extractors() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// NewExtractor builds the registered extractor spec refers to. A spec is a
+// registered name optionally followed by a colon and a parameter, such as
+// "deadline", "otel", or "value:request_id".
+func NewExtractor(spec string, options map[string]string) (ContextExtractor, error) {
+	named, err := NewNamedExtractor(spec, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return named.Extractor, nil
+}
+
+// NewNamedExtractor builds the registered extractor spec refers to like
+// NewExtractor, named spec and declaring the fields it was registered with.
+// It can be rebuilt with other options by ContextLogger.ReconfigureExtractor.
+func NewNamedExtractor(spec string, options map[string]string) (NamedExtractor, error) {
+	name, param, _ := strings.Cut(spec, ":")
+
+	registryMu.RLock()
+	r, ok := factories[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return NamedExtractor{}, fmt.Errorf("unknown extractor %q", name)
+	}
+
+	options = copyNames(options)
+
+	extractor, err := r.factory(param, options)
+	if err != nil {
+		return NamedExtractor{}, fmt.Errorf("extractor %q: %w", spec, err)
+	}
+
+	named := NamedExtractor{Name: spec, Extractor: extractor, spec: spec, options: options}
+	if r.fields != nil {
+		named.Fields = r.fields(param)
+	}
+
+	return named, nil
+}
+
+var errParamRequired = errors.New("a parameter is required")
+
+// noParam adapts an extractor constructor without arguments to a factory.
+func noParam(newExtractor func() ContextExtractor) ExtractorFactory {
+	return func(param string, _ map[string]string) (ContextExtractor, error) {
+		if param != "" {
+			return nil, fmt.Errorf("unexpected parameter %q", param)
+		}
+
+		return newExtractor(), nil
+	}
+}
+
+// declared returns the fields of a registration that declares specs whatever
+// its parameter.
+func declared(specs ...FieldSpec) func(string) []FieldSpec {
+	specs = append([]FieldSpec{}, specs...)
+
+	return func(string) []FieldSpec {
+		return specs
+	}
+}
+
+// newCarrierExtractor builds WithContextCarrier with the field name param.
+func newCarrierExtractor(param string, _ map[string]string) (ContextExtractor, error) {
+	if param == "" {
+		return nil, errParamRequired
+	}
+
+	return WithContextCarrier(param), nil
+}
+
+// newValueExtractor builds WithValue for the key registered as param. The
+// "default" option sets the value logged when the key is absent.
+func newValueExtractor(param string, options map[string]string) (ContextExtractor, error) {
+	if param == "" {
+		return nil, errParamRequired
+	}
+
+	registryMu.RLock()
+	key, ok := valueKeys[param]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no context key registered as %q", param)
+	}
+
+	opts := []ValueOption{ValueName(param)}
+	if def, ok := options["default"]; ok {
+		opts = append(opts, ValueDefault(def))
+	}
+
+	return WithValue(key, opts...), nil
+}
+
+// valueFields declares the field of the "value" extractor for the key
+// registered as param, whose type is only known at runtime.
+func valueFields(param string) []FieldSpec {
+	return []FieldSpec{{Key: param, Type: TypeAny}}
+}
@@ -0,0 +1,118 @@
+package contextlogger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const registryTestKey = contextKeyString("registry_request_id")
+
+func init() {
+	RegisterExtractor("test_static", func(param string, options map[string]string) (ContextExtractor, error) {
+		if reason, ok := options["fail"]; ok {
+			return nil, errors.New(reason)
+		}
+
+		fields := []zap.Field{zap.String("static", param)}
+		if suffix, ok := options["suffix"]; ok {
+			fields = append(fields, zap.String("suffix", suffix))
+		}
+
+		return func(context.Context) []zap.Field { return fields }, nil
+	}, FieldSpec{Key: "static", Type: TypeString}, FieldSpec{Key: "suffix", Type: TypeString})
+	RegisterValue("request_id", registryTestKey)
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	factory := func(string, map[string]string) (ContextExtractor, error) { return nil, nil }
+
+	require.PanicsWithValue(t, "contextlogger: RegisterExtractor called twice for deadline", func() {
+		RegisterExtractor("deadline", factory)
+	})
+	require.Panics(t, func() { RegisterExtractor("", factory) })
+	require.Panics(t, func() { RegisterExtractor("a:b", factory) })
+	require.Panics(t, func() { RegisterExtractor("nil_factory", nil) })
+
+	require.PanicsWithValue(t, "contextlogger: RegisterValue called twice for request_id", func() {
+		RegisterValue("request_id", registryTestKey)
+	})
+	require.Panics(t, func() { RegisterValue("", registryTestKey) })
+
+	require.Subset(t, Extractors(), []string{"bag", "carrier", "deadline", "fields", "test_static", "value"})
+	require.IsNonDecreasing(t, Extractors())
+}
+
+func TestNewExtractor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), registryTestKey, "req-1")
+
+	t.Run("passes parameter and options", func(t *testing.T) {
+		extractor, err := NewExtractor("test_static:abc", map[string]string{"suffix": "x"})
+		require.NoError(t, err)
+		require.Equal(t, []zap.Field{zap.String("static", "abc"), zap.String("suffix", "x")}, extractor(ctx))
+	})
+
+	t.Run("builds registered values", func(t *testing.T) {
+		extractor, err := NewExtractor("value:request_id", nil)
+		require.NoError(t, err)
+		require.Equal(t, []zap.Field{zap.Any("request_id", "req-1")}, extractor(ctx))
+
+		withDefault, err := NewExtractor("value:request_id", map[string]string{"default": "none"})
+		require.NoError(t, err)
+		require.Equal(t, []zap.Field{zap.Any("request_id", "none")}, withDefault(context.Background()))
+	})
+
+	t.Run("builds built-ins", func(t *testing.T) {
+		for _, spec := range []string{"deadline", "fields", "bag", "carrier:ctx"} {
+			extractor, err := NewExtractor(spec, nil)
+			require.NoError(t, err, spec)
+			require.NotNil(t, extractor, spec)
+		}
+	})
+
+	t.Run("rejects invalid specs", func(t *testing.T) {
+		for _, spec := range []string{"unknown", "deadline:x", "carrier", "value", "value:unregistered"} {
+			_, err := NewExtractor(spec, nil)
+			require.Error(t, err, spec)
+		}
+	})
+}
+
+func TestNewNamedExtractor(t *testing.T) {
+	t.Run("names the extractor and declares its fields", func(t *testing.T) {
+		named, err := NewNamedExtractor("value:request_id", nil)
+		require.NoError(t, err)
+		require.Equal(t, "value:request_id", named.Name)
+		require.Equal(t, []FieldSpec{{Key: "request_id", Type: TypeAny}}, named.Fields)
+	})
+
+	t.Run("declares no fields for the carrier", func(t *testing.T) {
+		named, err := NewNamedExtractor("carrier:ctx", nil)
+		require.NoError(t, err)
+		require.NotNil(t, named.Fields)
+		require.Empty(t, named.Fields)
+	})
+
+	t.Run("leaves extractors registered without fields undeclared", func(t *testing.T) {
+		named, err := NewNamedExtractor("fields", nil)
+		require.NoError(t, err)
+		require.Nil(t, named.Fields)
+	})
+
+	t.Run("can be reconfigured", func(t *testing.T) {
+		named, err := NewNamedExtractor("test_static", nil)
+		require.NoError(t, err)
+
+		cl := New(nil).WithNamed(named)
+		require.NoError(t, cl.ReconfigureExtractor("test_static", map[string]string{"suffix": "x"}))
+		require.Equal(t, map[string]string{"suffix": "x"}, cl.ExtractorStats()[0].Options)
+	})
+
+	t.Run("reports errors", func(t *testing.T) {
+		_, err := NewNamedExtractor("unknown", nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,149 @@
+package contextlogger
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RenameFields renames extracted fields whose key appears in names to the
+// mapped key, so the same extractors can follow the naming scheme of each log
+// backend. Only the keys change; values are logged as extracted. Fields
+// grouped with Namespace are renamed inside the group, while call-site fields
+// are left alone. Later mappings for the same key replace earlier ones,
+// including any value conversion a preset such as DatadogFieldNames set.
+func RenameFields(names map[string]string) Option {
+	names = copyNames(names)
+
+	return func(o *options) {
+		if len(names) == 0 {
+			return
+		}
+
+		merged := copyNames(o.names)
+		if merged == nil {
+			merged = make(map[string]string, len(names))
+		}
+
+		for from, to := range names {
+			merged[from] = to
+		}
+
+		o.names = merged
+
+		if len(o.converters) == 0 {
+			return
+		}
+
+		converters := make(map[string]func(zap.Field) zap.Field, len(o.converters))
+		for from, convert := range o.converters {
+			if _, ok := names[from]; !ok {
+				converters[from] = convert
+			}
+		}
+
+		o.converters = converters
+	}
+}
+
+// ECSFieldNames renames the trace correlation fields to the Elastic Common
+// Schema names trace.id and span.id.
+func ECSFieldNames() Option {
+	return RenameFields(map[string]string{
+		"trace_id": "trace.id",
+		"span_id":  "span.id",
+	})
+}
+
+// OTelFieldNames renames the trace correlation fields to the OpenTelemetry log
+// data model names TraceId and SpanId.
+func OTelFieldNames() Option {
+	return RenameFields(map[string]string{
+		"trace_id": "TraceId",
+		"span_id":  "SpanId",
+	})
+}
+
+// DatadogFieldNames renames the trace correlation fields to the Datadog names
+// dd.trace_id and dd.span_id. Datadog links logs to traces only by decimal
+// 64-bit IDs, so hex IDs such as the OpenTelemetry and Sentry ones are also
+// converted to the decimal form of their lower 64 bits. Values other than
+// 16- or 32-digit hex strings are logged unchanged.
+func DatadogFieldNames() Option {
+	rename := RenameFields(map[string]string{
+		"trace_id": "dd.trace_id",
+		"span_id":  "dd.span_id",
+	})
+
+	return func(o *options) {
+		rename(o)
+
+		converters := make(map[string]func(zap.Field) zap.Field, len(o.converters)+2)
+		for from, convert := range o.converters {
+			converters[from] = convert
+		}
+
+		converters["trace_id"] = datadogID
+		converters["span_id"] = datadogID
+		o.converters = converters
+	}
+}
+
+// datadogID converts a 16- or 32-digit hex trace or span ID to the decimal
+// form of its lower 64 bits.
+func datadogID(field zap.Field) zap.Field {
+	if field.Type != zapcore.StringType || (len(field.String) != 16 && len(field.String) != 32) {
+		return field
+	}
+
+	if strings.TrimLeft(field.String, "0123456789abcdefABCDEF") != "" {
+		return field
+	}
+
+	id, err := strconv.ParseUint(field.String[len(field.String)-16:], 16, 64)
+	if err != nil {
+		return field
+	}
+
+	return zap.String(field.Key, strconv.FormatUint(id, 10))
+}
+
+func copyNames(names map[string]string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]string, len(names))
+	for from, to := range names {
+		copied[from] = to
+	}
+
+	return copied
+}
+
+// renameFields renames fields in place, converting the values of keys in
+// converters first, including the members of Namespace groups, which are
+// copied before renaming.
+func renameFields(fields []zap.Field, names map[string]string, converters map[string]func(zap.Field) zap.Field) {
+	for i := range fields {
+		if convert, ok := converters[fields[i].Key]; ok {
+			fields[i] = convert(fields[i])
+		}
+
+		if name, ok := names[fields[i].Key]; ok {
+			fields[i].Key = name
+		}
+
+		if fields[i].Type != zapcore.ObjectMarshalerType {
+			continue
+		}
+
+		if group, ok := fields[i].Interface.(fieldsObject); ok {
+			renamed := append(fieldsObject(nil), group...)
+			renameFields(renamed, names, converters)
+			fields[i].Interface = renamed
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package contextlogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRenameFields(t *testing.T) {
+	ctx := WithFields(context.Background(),
+		zap.String("trace_id", "abc"),
+		zap.String("span_id", "def"),
+		zap.String("user", "u-1"),
+	)
+
+	t.Run("renames extracted fields only", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithFieldsExtractor()).WithOptions(RenameFields(map[string]string{"user": "user.id"}))
+
+		cl.Info(ctx, "renamed", zap.String("user", "call-site"))
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "u-1", fields["user.id"])
+		require.Equal(t, "call-site", fields["user"])
+		require.Equal(t, "abc", fields["trace_id"])
+	})
+
+	t.Run("later mappings replace earlier ones", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithFieldsExtractor()).WithOptions(
+			RenameFields(map[string]string{"user": "first", "span_id": "span"}),
+			RenameFields(map[string]string{"user": "second"}),
+		)
+
+		fields := logAndAssert(t, ctx, observed, cl, "merged")
+		require.Equal(t, "u-1", fields["second"])
+		require.Equal(t, "def", fields["span"])
+		require.NotContains(t, fields, "first")
+	})
+
+	t.Run("renames inside namespaces without mutating the group", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		namespace := Namespace("ctx", WithFieldsExtractor())
+		cl := New(logger, namespace).WithOptions(ECSFieldNames())
+
+		fields := logAndAssert(t, ctx, observed, cl, "grouped")
+		require.Equal(t, map[string]interface{}{"trace.id": "abc", "span.id": "def", "user": "u-1"}, fields["ctx"])
+
+		unrenamed := logAndAssert(t, ctx, observed, New(logger, namespace), "plain")
+		require.Equal(t, map[string]interface{}{"trace_id": "abc", "span_id": "def", "user": "u-1"}, unrenamed["ctx"])
+	})
+
+	t.Run("snapshots keep renamed fields", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithFieldsExtractor()).WithOptions(DatadogFieldNames())
+
+		fields := logAndAssert(t, cl.Attach(ctx), observed, cl, "attached")
+		require.Equal(t, "abc", fields["dd.trace_id"])
+		require.Equal(t, "def", fields["dd.span_id"])
+	})
+
+	t.Run("presets", func(t *testing.T) {
+		presets := map[string]struct {
+			option      Option
+			trace, span string
+		}{
+			"ecs":     {ECSFieldNames(), "trace.id", "span.id"},
+			"otel":    {OTelFieldNames(), "TraceId", "SpanId"},
+			"datadog": {DatadogFieldNames(), "dd.trace_id", "dd.span_id"},
+		}
+
+		for name, preset := range presets {
+			t.Run(name, func(t *testing.T) {
+				logger, observed := newTestLogger()
+				cl := New(logger, WithFieldsExtractor()).WithOptions(preset.option)
+
+				fields := logAndAssert(t, ctx, observed, cl, name)
+				require.Equal(t, "abc", fields[preset.trace])
+				require.Equal(t, "def", fields[preset.span])
+				require.NotContains(t, fields, "trace_id")
+			})
+		}
+	})
+
+	t.Run("datadog converts hex IDs to decimal", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithFieldsExtractor()).WithOptions(DatadogFieldNames())
+		hexCtx := WithFields(context.Background(),
+			zap.String("trace_id", "4bf92f3577b34da6a3ce929d0e0e4736"),
+			zap.String("span_id", "00f067aa0ba902b7"),
+		)
+
+		fields := logAndAssert(t, hexCtx, observed, cl, "datadog")
+		require.Equal(t, "11803532876627986230", fields["dd.trace_id"])
+		require.Equal(t, "67667974448284343", fields["dd.span_id"])
+
+		overridden := cl.WithOptions(RenameFields(map[string]string{"trace_id": "trace"}))
+		fields = logAndAssert(t, hexCtx, observed, overridden, "overridden")
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields["trace"])
+		require.Equal(t, "67667974448284343", fields["dd.span_id"])
+	})
+
+	t.Run("ignores empty mappings", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithFieldsExtractor()).WithOptions(RenameFields(nil))
+
+		require.Equal(t, "abc", logAndAssert(t, ctx, observed, cl, "empty")["trace_id"])
+	})
+}
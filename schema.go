@@ -0,0 +1,259 @@
+package contextlogger
+
+import "encoding/json"
+
+// FieldType is the kind of value an extracted field holds, as far as index
+// mappings are concerned.
+type FieldType string
+
+const (
+	// TypeString is a string, including byte strings, stringers, and errors.
+	TypeString FieldType = "string"
+	// TypeInteger is a signed or unsigned integer.
+	TypeInteger FieldType = "integer"
+	// TypeNumber is a floating-point number.
+	TypeNumber FieldType = "number"
+	// TypeBoolean is a boolean.
+	TypeBoolean FieldType = "boolean"
+	// TypeTime is a time.Time, encoded as the encoder's EncodeTime decides.
+	TypeTime FieldType = "time"
+	// TypeDuration is a time.Duration, encoded as the encoder's
+	// EncodeDuration decides.
+	TypeDuration FieldType = "duration"
+	// TypeObject is a nested object.
+	TypeObject FieldType = "object"
+	// TypeArray is an array.
+	TypeArray FieldType = "array"
+	// TypeAny is a value whose type is only known at runtime.
+	TypeAny FieldType = "any"
+)
+
+// FieldSpec describes a field an extractor may add.
+type FieldSpec struct {
+	Key  string    `json:"key"`
+	Type FieldType `json:"type"`
+	// Fields lists the known members of an object.
+	Fields []FieldSpec `json:"fields,omitempty"`
+	// Elem is the element type of an array, if known.
+	Elem FieldType `json:"elem,omitempty"`
+}
+
+// Schema describes the fields a ContextLogger may add to its entries.
+type Schema struct {
+	Fields []FieldSpec `json:"fields"`
+	// Undeclared names the extractors that declare no fields, such as
+	// WithFieldsExtractor; set NamedExtractor.Fields to describe them.
+	Undeclared []string `json:"undeclared,omitempty"`
+}
+
+// Schema describes the fields the extractors of the ContextLogger may add,
+// including disabled ones, after renaming, grouping, and limits are applied.
+// Fields are taken from NamedExtractor.Fields and from the declarations of
+// registered extractors, so extractors are never run to discover them. Fields
+// added inline by LogFielder values and keys made unique by SuffixDuplicates
+// are not described.
+func (c *ContextLogger) Schema() Schema {
+	var (
+		sets   []*extractorSet
+		schema Schema
+	)
+
+	for s := c.extractors; s != nil; s = s.parent {
+		sets = append(sets, s)
+	}
+
+	for i := len(sets) - 1; i >= 0; i-- {
+		for _, entry := range sets[i].load() {
+			if entry.fields == nil {
+				schema.Undeclared = append(schema.Undeclared, entry.Name())
+				continue
+			}
+
+			schema.Fields = mergeSpecs(schema.Fields, entry.fields...)
+		}
+	}
+
+	schema.Fields = groupSpecs(c.opts.namespace, schema.Fields)
+
+	if len(c.opts.names) > 0 {
+		schema.Fields = renameSpecs(schema.Fields, c.opts.names)
+	}
+
+	schema.Fields = mergeSpecs(schema.Fields, extractorPanicSpec)
+
+	if c.opts.limits != (Limits{}) {
+		schema.Fields = mergeSpecs(schema.Fields, FieldSpec{Key: FieldContextTruncated, Type: TypeArray, Elem: TypeString})
+	}
+
+	if schema.Fields == nil {
+		schema.Fields = []FieldSpec{}
+	}
+
+	return schema
+}
+
+var extractorPanicSpec = FieldSpec{Key: FieldContextExtractorError, Type: TypeObject, Fields: []FieldSpec{
+	{Key: "extractor", Type: TypeString},
+	{Key: "panic", Type: TypeString},
+}}
+
+// mergeSpecs adds specs to dst. Specs sharing a key with different types
+// become TypeAny, and the members of objects are merged.
+func mergeSpecs(dst []FieldSpec, specs ...FieldSpec) []FieldSpec {
+	for _, spec := range specs {
+		i := indexSpec(dst, spec.Key)
+		if i < 0 {
+			dst = append(dst, spec)
+			continue
+		}
+
+		existing := dst[i]
+
+		switch {
+		case existing.Type != spec.Type:
+			dst[i] = FieldSpec{Key: spec.Key, Type: TypeAny}
+		case spec.Type == TypeObject:
+			dst[i].Fields = mergeSpecs(append([]FieldSpec(nil), existing.Fields...), spec.Fields...)
+		case existing.Elem != spec.Elem:
+			dst[i].Elem = ""
+		}
+	}
+
+	return dst
+}
+
+func indexSpec(specs []FieldSpec, key string) int {
+	for i := range specs {
+		if specs[i].Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// groupSpecs nests specs under name like nestFields, keeping the extractor
+// panic report at the top level.
+func groupSpecs(name string, specs []FieldSpec) []FieldSpec {
+	if name == "" || len(specs) == 0 {
+		return specs
+	}
+
+	var nested, topLevel []FieldSpec
+
+	for _, spec := range specs {
+		if spec.Key == FieldContextExtractorError {
+			topLevel = append(topLevel, spec)
+		} else {
+			nested = append(nested, spec)
+		}
+	}
+
+	if len(nested) == 0 {
+		return topLevel
+	}
+
+	return mergeSpecs(topLevel, FieldSpec{Key: name, Type: TypeObject, Fields: nested})
+}
+
+// renameSpecs renames specs and the members of objects like renameFields.
+func renameSpecs(specs []FieldSpec, names map[string]string) []FieldSpec {
+	renamed := make([]FieldSpec, 0, len(specs))
+
+	for _, spec := range specs {
+		if name, ok := names[spec.Key]; ok {
+			spec.Key = name
+		}
+
+		if len(spec.Fields) > 0 {
+			spec.Fields = renameSpecs(spec.Fields, names)
+		}
+
+		renamed = mergeSpecs(renamed, spec)
+	}
+
+	return renamed
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for the fields. Times and
+// durations accept strings and numbers, since their encoding depends on the
+// zap encoder configuration.
+func (s Schema) JSONSchema() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": jsonSchemaProperties(s.Fields),
+	})
+}
+
+func jsonSchemaProperties(specs []FieldSpec) map[string]any {
+	properties := make(map[string]any, len(specs))
+	for _, spec := range specs {
+		properties[spec.Key] = jsonSchemaType(spec.Type, spec)
+	}
+
+	return properties
+}
+
+func jsonSchemaType(typ FieldType, spec FieldSpec) map[string]any {
+	switch typ {
+	case TypeString, TypeInteger, TypeNumber, TypeBoolean:
+		return map[string]any{"type": string(typ)}
+	case TypeTime, TypeDuration:
+		return map[string]any{"type": []string{"string", "number"}}
+	case TypeObject:
+		return map[string]any{"type": "object", "properties": jsonSchemaProperties(spec.Fields)}
+	case TypeArray:
+		if spec.Elem == "" {
+			return map[string]any{"type": "array"}
+		}
+
+		return map[string]any{"type": "array", "items": jsonSchemaType(spec.Elem, FieldSpec{})}
+	default:
+		return map[string]any{}
+	}
+}
+
+// ElasticsearchMapping returns an Elasticsearch index mapping for the fields.
+// Times accept ISO 8601 strings and epoch seconds, and durations map to
+// double, matching zap's production encoders. Fields of type TypeAny and
+// arrays of unknown elements are left to dynamic mapping.
+func (s Schema) ElasticsearchMapping() ([]byte, error) {
+	return json.Marshal(map[string]any{"properties": elasticsearchProperties(s.Fields)})
+}
+
+func elasticsearchProperties(specs []FieldSpec) map[string]any {
+	properties := make(map[string]any, len(specs))
+
+	for _, spec := range specs {
+		typ := spec.Type
+		if typ == TypeArray {
+			typ = spec.Elem
+		}
+
+		if mapping, ok := elasticsearchType(typ, spec); ok {
+			properties[spec.Key] = mapping
+		}
+	}
+
+	return properties
+}
+
+func elasticsearchType(typ FieldType, spec FieldSpec) (map[string]any, bool) {
+	switch typ {
+	case TypeString:
+		return map[string]any{"type": "keyword"}, true
+	case TypeInteger:
+		return map[string]any{"type": "long"}, true
+	case TypeNumber, TypeDuration:
+		return map[string]any{"type": "double"}, true
+	case TypeBoolean:
+		return map[string]any{"type": "boolean"}, true
+	case TypeTime:
+		return map[string]any{"type": "date", "format": "strict_date_optional_time||epoch_second"}, true
+	case TypeObject:
+		return map[string]any{"properties": elasticsearchProperties(spec.Fields)}, true
+	default:
+		return nil, false
+	}
+}
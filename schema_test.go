@@ -0,0 +1,161 @@
+package contextlogger
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContextLogger_Schema(t *testing.T) {
+	t.Run("describes registered extractors", func(t *testing.T) {
+		cl, err := NewFromConfig(nil, Config{Extractors: []ExtractorConfig{
+			{Name: "value:request_id"},
+			{Name: "deadline"},
+			{Name: "carrier:ctx"},
+			{Name: "test_static"},
+		}})
+		require.NoError(t, err)
+
+		schema := cl.Schema()
+
+		require.Empty(t, schema.Undeclared)
+		require.Equal(t, []FieldSpec{
+			{Key: "request_id", Type: TypeAny},
+			{Key: FieldContextDeadlineAt, Type: TypeTime},
+			{Key: FieldContextTimeLeft, Type: TypeDuration},
+			{Key: FieldContextError, Type: TypeString},
+			{Key: FieldContextCause, Type: TypeString},
+			{Key: "static", Type: TypeString},
+			{Key: "suffix", Type: TypeString},
+			extractorPanicSpec,
+		}, schema.Fields)
+	})
+
+	t.Run("lists undeclared extractors without running them", func(t *testing.T) {
+		var calls atomic.Int32
+		generated := func(context.Context) []zap.Field {
+			calls.Add(1)
+			return []zap.Field{zap.String("trace_id", "generated")}
+		}
+
+		cl, err := NewFromConfig(nil, Config{Extractors: []ExtractorConfig{{Name: "fields"}}})
+		require.NoError(t, err)
+
+		schema := cl.With(generated, panickingExtractor).Schema()
+
+		require.Zero(t, calls.Load())
+		require.Len(t, schema.Undeclared, 3)
+		require.Equal(t, "fields", schema.Undeclared[0])
+		require.True(t, strings.HasPrefix(schema.Undeclared[1], "context-logger.TestContextLogger_Schema."), schema.Undeclared[1])
+		require.Equal(t, "context-logger.panickingExtractor", schema.Undeclared[2])
+		require.Equal(t, []FieldSpec{extractorPanicSpec}, schema.Fields)
+	})
+
+	t.Run("applies grouping, renaming and limits", func(t *testing.T) {
+		schema := New(nil).WithNamed(NamedExtractor{
+			Name:      "trace",
+			Extractor: WithFieldsExtractor(),
+			Fields:    []FieldSpec{{Key: "trace_id", Type: TypeString}},
+		}).WithOptions(ECSFieldNames(), GroupFields("ctx"), LimitFields(Limits{MaxFields: 10})).Schema()
+
+		require.Empty(t, schema.Undeclared)
+		require.Equal(t, []FieldSpec{
+			{Key: "ctx", Type: TypeObject, Fields: []FieldSpec{{Key: "trace.id", Type: TypeString}}},
+			extractorPanicSpec,
+			{Key: FieldContextTruncated, Type: TypeArray, Elem: TypeString},
+		}, schema.Fields)
+	})
+
+	t.Run("merges fields declared by several extractors", func(t *testing.T) {
+		schema := New(nil).WithNamed(
+			NamedExtractor{Name: "int", Extractor: WithFieldsExtractor(), Fields: []FieldSpec{{Key: "id", Type: TypeInteger}}},
+			NamedExtractor{Name: "string", Extractor: WithBagExtractor(), Fields: []FieldSpec{
+				{Key: "id", Type: TypeString},
+				{Key: "extra", Type: TypeNumber},
+			}},
+			NamedExtractor{Name: "none", Extractor: WithContextCarrier("ctx"), Fields: []FieldSpec{}},
+		).Schema()
+
+		require.Empty(t, schema.Undeclared)
+		require.Equal(t, []FieldSpec{
+			{Key: "id", Type: TypeAny},
+			{Key: "extra", Type: TypeNumber},
+			extractorPanicSpec,
+		}, schema.Fields)
+	})
+
+	t.Run("includes disabled extractors and options namespace", func(t *testing.T) {
+		cl, err := NewFromConfig(nil, Config{
+			Extractors: []ExtractorConfig{{Name: "deadline", Disabled: true}},
+			Namespace:  "ctx",
+		})
+		require.NoError(t, err)
+
+		fields := cl.Schema().Fields
+		require.Len(t, fields, 2)
+		require.Equal(t, "ctx", fields[0].Key)
+		require.Len(t, fields[0].Fields, 4)
+	})
+
+	t.Run("keeps declarations of reconfigured extractors", func(t *testing.T) {
+		cl, err := NewFromConfig(nil, Config{Extractors: []ExtractorConfig{{Name: "test_static"}}})
+		require.NoError(t, err)
+
+		before := cl.Schema()
+		require.NoError(t, cl.ReconfigureExtractor("test_static", map[string]string{"suffix": "x"}))
+		require.Equal(t, before, cl.Schema())
+	})
+}
+
+func TestSchema_Exports(t *testing.T) {
+	schema := Schema{Fields: []FieldSpec{
+		{Key: "request_id", Type: TypeString},
+		{Key: "attempt", Type: TypeInteger},
+		{Key: "ratio", Type: TypeNumber},
+		{Key: "sampled", Type: TypeBoolean},
+		{Key: "deadline", Type: TypeTime},
+		{Key: "left", Type: TypeDuration},
+		{Key: "ctx", Type: TypeObject, Fields: []FieldSpec{{Key: "trace.id", Type: TypeString}}},
+		{Key: "truncated", Type: TypeArray, Elem: TypeString},
+		{Key: "list", Type: TypeArray},
+		{Key: "payload", Type: TypeAny},
+	}}
+
+	jsonSchema, err := schema.JSONSchema()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"request_id": {"type": "string"},
+			"attempt": {"type": "integer"},
+			"ratio": {"type": "number"},
+			"sampled": {"type": "boolean"},
+			"deadline": {"type": ["string", "number"]},
+			"left": {"type": ["string", "number"]},
+			"ctx": {"type": "object", "properties": {"trace.id": {"type": "string"}}},
+			"truncated": {"type": "array", "items": {"type": "string"}},
+			"list": {"type": "array"},
+			"payload": {}
+		}
+	}`, string(jsonSchema))
+
+	mapping, err := schema.ElasticsearchMapping()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"properties": {
+			"request_id": {"type": "keyword"},
+			"attempt": {"type": "long"},
+			"ratio": {"type": "double"},
+			"sampled": {"type": "boolean"},
+			"deadline": {"type": "date", "format": "strict_date_optional_time||epoch_second"},
+			"left": {"type": "double"},
+			"ctx": {"properties": {"trace.id": {"type": "keyword"}}},
+			"truncated": {"type": "keyword"}
+		}
+	}`, string(mapping))
+}
@@ -3,7 +3,7 @@ module github.com/adlandh/context-logger/sentry-extractor
 go 1.25.0
 
 require (
-	github.com/adlandh/context-logger v1.6.6
+	github.com/adlandh/context-logger v1.7.0
 	github.com/getsentry/sentry-go v0.48.0
 	github.com/stretchr/testify v1.12.0
 	go.uber.org/zap v1.28.0
@@ -15,3 +15,8 @@ require (
 	golang.org/x/text v0.37.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// The extractor registry this module registers with first ships in core
+// v1.7.0, which must be tagged before this module. Until then, and for
+// development in this repository, build against the core module here.
+replace github.com/adlandh/context-logger => ../
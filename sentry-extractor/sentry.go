@@ -4,12 +4,17 @@ package sentryextractor
 
 import (
 	"context"
+	"fmt"
 
 	ctxLogger "github.com/adlandh/context-logger"
 	"github.com/getsentry/sentry-go"
 	"go.uber.org/zap"
 )
 
+// ExtractorName is the name With is registered under, with the fields it
+// adds, for contextlogger.NewFromConfig when this package is imported.
+const ExtractorName = "sentry"
+
 const (
 	// FieldTraceID identifies the Sentry trace ID field.
 	FieldTraceID = "trace_id"
@@ -21,6 +26,23 @@ const (
 	FieldSpanOp = "span_op"
 )
 
+var fieldSpecs = []ctxLogger.FieldSpec{
+	{Key: FieldTraceID, Type: ctxLogger.TypeString},
+	{Key: FieldSpanID, Type: ctxLogger.TypeString},
+	{Key: FieldSpanStatus, Type: ctxLogger.TypeString},
+	{Key: FieldSpanOp, Type: ctxLogger.TypeString},
+}
+
+func init() {
+	ctxLogger.RegisterExtractor(ExtractorName, func(param string, _ map[string]string) (ctxLogger.ContextExtractor, error) {
+		if param != "" {
+			return nil, fmt.Errorf("unexpected parameter %q", param)
+		}
+
+		return With(), nil
+	}, fieldSpecs...)
+}
+
 // With returns an extractor for fields from the Sentry span in a context.
 func With() ctxLogger.ContextExtractor {
 	return func(ctx context.Context) []zap.Field {
@@ -194,3 +194,35 @@ func TestSentryExtractor_SpanStatus(t *testing.T) {
 		require.Equal(t, sentry.SpanStatusInternalError.String(), fields[FieldSpanStatus])
 	})
 }
+
+func TestSentryExtractor_Registered(t *testing.T) {
+	logger, observed := newTestLogger()
+	cl, err := ctxLogger.NewFromConfig(logger, ctxLogger.Config{
+		Extractors: []ctxLogger.ExtractorConfig{{Name: ExtractorName}},
+	})
+	require.NoError(t, err)
+
+	span := sentry.StartSpan(context.Background(), "registered")
+	defer span.Finish()
+
+	fields := logAndAssert(t, span.Context(), observed, cl, "registered")
+	require.Equal(t, span.TraceID.String(), fields[FieldTraceID])
+	require.Equal(t, span.SpanID.String(), fields[FieldSpanID])
+
+	_, err = ctxLogger.NewExtractor(ExtractorName+":param", nil)
+	require.Error(t, err)
+}
+
+func TestSentryExtractor_Schema(t *testing.T) {
+	cl, err := ctxLogger.NewFromConfig(nil, ctxLogger.Config{
+		Extractors: []ctxLogger.ExtractorConfig{{Name: ExtractorName}},
+	})
+	require.NoError(t, err)
+
+	schema := cl.Schema()
+
+	require.Empty(t, schema.Undeclared)
+	require.Contains(t, schema.Fields, ctxLogger.FieldSpec{Key: FieldTraceID, Type: ctxLogger.TypeString})
+	require.Contains(t, schema.Fields, ctxLogger.FieldSpec{Key: FieldSpanStatus, Type: ctxLogger.TypeString})
+	require.Contains(t, schema.Fields, ctxLogger.FieldSpec{Key: FieldSpanOp, Type: ctxLogger.TypeString})
+}
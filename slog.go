@@ -0,0 +1,209 @@
+package contextlogger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandler is a slog.Handler that writes records through the core of a
+// ContextLogger and adds the fields its extractors find in each record's
+// context.
+type SlogHandler struct {
+	logger *ContextLogger
+	core   zapcore.Core
+	fields []zap.Field
+	groups []string
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// NewSlogHandler creates a SlogHandler and falls back to a no-op logger when
+// logger is nil.
+func NewSlogHandler(logger *ContextLogger) *SlogHandler {
+	if logger == nil {
+		logger = New(nil)
+	}
+
+	return &SlogHandler{
+		logger: logger,
+		core:   logger.Logger().Core(),
+	}
+}
+
+// Enabled reports whether the underlying core logs records at level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(zapLevel(level))
+}
+
+// Handle writes record with the fields extracted from ctx at the top level,
+// followed by the handler's attributes and the record's attributes. Extractors
+// run only when the core accepts the entry. Errors from writing it are
+// returned with the text zap reports for them. A nil context is treated as
+// context.Background().
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	ent := zapcore.Entry{
+		LoggerName: h.logger.Logger().Name(),
+		Time:       record.Time,
+		Level:      zapLevel(record.Level),
+		Message:    record.Message,
+	}
+
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, frame.PC != 0)
+		ent.Caller.Function = frame.Function
+	}
+
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	attrs := make([]zap.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = appendAttr(attrs, attr)
+		return true
+	})
+
+	fields := make([]zap.Field, 0, len(h.fields)+len(h.groups)+len(attrs))
+	fields = append(fields, h.fields...)
+
+	if len(attrs) > 0 {
+		fields = appendNamespaces(fields, h.groups)
+		fields = append(fields, attrs...)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var errs reportedErrors
+
+	ce.ErrorOutput = &errs
+	ce.Write(h.logger.extract(ctx, fields)...)
+
+	return errs.err
+}
+
+// reportedErrors collects the write errors a CheckedEntry reports, which zap
+// only reports as text, so that Handle can return them.
+type reportedErrors struct {
+	err error
+}
+
+func (r *reportedErrors) Write(p []byte) (int, error) {
+	r.err = errors.Join(r.err, errors.New(string(bytes.TrimSpace(p))))
+
+	return len(p), nil
+}
+
+func (*reportedErrors) Sync() error {
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record, inside the
+// groups opened so far.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	converted := appendAttrs(nil, attrs)
+	if len(converted) == 0 {
+		return h
+	}
+
+	clone := *h
+	clone.fields = make([]zap.Field, 0, len(h.fields)+len(h.groups)+len(converted))
+	clone.fields = append(clone.fields, h.fields...)
+	clone.fields = appendNamespaces(clone.fields, h.groups)
+	clone.fields = append(clone.fields, converted...)
+	clone.groups = nil
+
+	return &clone
+}
+
+// WithGroup returns a handler that nests subsequent attributes under name.
+// Groups without attributes are omitted.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+
+	return &clone
+}
+
+// zapLevel maps a slog level to the nearest zap level at or below it.
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func appendNamespaces(fields []zap.Field, groups []string) []zap.Field {
+	for _, group := range groups {
+		fields = append(fields, zap.Namespace(group))
+	}
+
+	return fields
+}
+
+func appendAttrs(fields []zap.Field, attrs []slog.Attr) []zap.Field {
+	for _, attr := range attrs {
+		fields = appendAttr(fields, attr)
+	}
+
+	return fields
+}
+
+// appendAttr converts attr to zap fields following the slog.Handler rules:
+// empty attributes and groups are dropped and groups without a key are
+// inlined.
+func appendAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		if attr.Key == "" {
+			return appendAttrs(fields, attr.Value.Group())
+		}
+
+		group := appendAttrs(nil, attr.Value.Group())
+		if len(group) == 0 {
+			return fields
+		}
+
+		return append(fields, zap.Object(attr.Key, fieldsObject(group)))
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, attr.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, attr.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, attr.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, attr.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, attr.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, attr.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, attr.Value.Time()))
+	default:
+		return append(fields, zap.Any(attr.Key, attr.Value.Any()))
+	}
+}
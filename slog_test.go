@@ -0,0 +1,141 @@
+package contextlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlogHandler_Conformance(t *testing.T) {
+	var buf bytes.Buffer
+
+	newHandler := func(t *testing.T) slog.Handler {
+		t.Helper()
+		buf.Reset()
+
+		encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			TimeKey:     slog.TimeKey,
+			LevelKey:    slog.LevelKey,
+			MessageKey:  slog.MessageKey,
+			EncodeTime:  zapcore.RFC3339NanoTimeEncoder,
+			EncodeLevel: zapcore.LowercaseLevelEncoder,
+		})
+
+		return NewSlogHandler(New(zap.New(zapcore.NewCore(encoder, zapcore.AddSync(&buf), zapcore.DebugLevel))))
+	}
+
+	result := func(t *testing.T) map[string]any {
+		t.Helper()
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+		return entry
+	}
+
+	slogtest.Run(t, newHandler, result)
+}
+
+func TestSlogHandler_Extractors(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+
+	t.Run("adds extracted fields at top level", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		sl := slog.New(NewSlogHandler(New(logger, WithValueExtractor(key))))
+
+		sl.With("component", "db").WithGroup("query").InfoContext(ctx, "slog-message", "rows", 3)
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "slog-message", entries[0].Message)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "req-1", fields[key.String()])
+		require.Equal(t, "test", fields["text"])
+		require.Equal(t, "db", fields["component"])
+		require.Equal(t, map[string]interface{}{"rows": int64(3)}, fields["query"])
+	})
+
+	t.Run("skips extractors for disabled levels", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		var calls atomic.Int32
+		sl := slog.New(NewSlogHandler(New(logger, countingExtractor(&calls))))
+
+		sl.DebugContext(ctx, "dropped")
+
+		require.Zero(t, calls.Load())
+		require.Empty(t, observed.TakeAll())
+		require.False(t, sl.Enabled(ctx, slog.LevelDebug))
+		require.True(t, sl.Enabled(ctx, slog.LevelInfo))
+	})
+
+	t.Run("maps levels and caller", func(t *testing.T) {
+		core, observed := observer.New(zap.DebugLevel)
+		sl := slog.New(NewSlogHandler(New(zap.New(core).Named("svc"))))
+
+		levels := map[slog.Level]zapcore.Level{
+			slog.LevelDebug:     zap.DebugLevel,
+			slog.LevelInfo:      zap.InfoLevel,
+			slog.LevelInfo + 2:  zap.InfoLevel,
+			slog.LevelWarn:      zap.WarnLevel,
+			slog.LevelError:     zap.ErrorLevel,
+			slog.LevelError + 4: zap.ErrorLevel,
+		}
+
+		for level, want := range levels {
+			sl.Log(ctx, level, "leveled")
+
+			entries := observed.TakeAll()
+			require.Len(t, entries, 1)
+			require.Equal(t, want, entries[0].Level)
+			require.Equal(t, "svc", entries[0].LoggerName)
+			require.True(t, entries[0].Caller.Defined)
+			require.Equal(t, "slog_test.go", filepath.Base(entries[0].Caller.File))
+		}
+	})
+
+	t.Run("handles nil context and nil logger", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		h := NewSlogHandler(New(logger, WithValueExtractor(key)))
+
+		//nolint:staticcheck // a nil context must not panic
+		require.NoError(t, h.Handle(nil, slog.NewRecord(time.Now(), slog.LevelInfo, "nil-context", 0)))
+		require.Len(t, observed.TakeAll(), 1)
+
+		require.NotPanics(t, func() {
+			slog.New(NewSlogHandler(nil)).InfoContext(ctx, "nop")
+		})
+	})
+}
+
+func TestSlogHandler_WriteError(t *testing.T) {
+	handler := NewSlogHandler(New(zap.New(failingCore{zap.InfoLevel})))
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "lost", 0)
+
+	err := handler.Handle(context.Background(), record)
+
+	require.ErrorContains(t, err, "write error: disk full")
+}
+
+func TestSlogHandler_TeedCores(t *testing.T) {
+	infoCore, infoObserved := observer.New(zap.InfoLevel)
+	debugCore, debugObserved := observer.New(zap.DebugLevel)
+	handler := NewSlogHandler(New(zap.New(zapcore.NewTee(infoCore, debugCore))))
+
+	err := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelDebug, "detail", 0))
+
+	require.NoError(t, err)
+	require.Zero(t, infoObserved.Len())
+	require.Equal(t, 1, debugObserved.Len())
+}
@@ -0,0 +1,64 @@
+package contextlogger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Snapshot holds the fields a ContextLogger extracted from a context at one
+// point in time. It lets background work, such as goroutine pools or
+// context.WithoutCancel contexts, log with the fields of the request that
+// started it after the request's context values or deadline are gone.
+type Snapshot struct {
+	attachment
+}
+
+// Snapshot runs the extractors against ctx and captures the result. Fields
+// already attached to ctx by c are captured as they are. A nil context is
+// treated as context.Background().
+func (c *ContextLogger) Snapshot(ctx context.Context) *Snapshot {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &Snapshot{attachment: *c.attach(ctx)}
+}
+
+// Restore returns a copy of ctx carrying snapshot, as if Attach had been
+// called on the context the snapshot was taken from. Logging through the
+// ContextLogger that took the snapshot, with the returned context or its
+// children, reuses the snapshot fields instead of running the extractors.
+// A nil snapshot leaves ctx unchanged, and a nil context is treated as
+// context.Background().
+func Restore(ctx context.Context, snapshot *Snapshot) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if snapshot == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, attachedContextKey{}, &snapshot.attachment)
+}
+
+// Fields returns a copy of the captured fields.
+func (s *Snapshot) Fields() []zap.Field {
+	if s == nil {
+		return nil
+	}
+
+	return append([]zap.Field(nil), s.fields...)
+}
+
+// Logger returns the underlying logger of the ContextLogger that took the
+// snapshot with the captured fields attached. A nil snapshot returns a no-op
+// logger.
+func (s *Snapshot) Logger() *zap.Logger {
+	if s == nil {
+		return nopLogger.Logger()
+	}
+
+	return s.owner.Logger().With(s.fields...)
+}
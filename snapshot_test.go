@@ -0,0 +1,80 @@
+package contextlogger
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContextLogger_Snapshot(t *testing.T) {
+	key := contextKeyString("request_id")
+
+	t.Run("restores fields into background context", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key), WithDeadlineExtractor())
+
+		reqCtx, cancel := context.WithTimeout(context.WithValue(context.Background(), key, "req-1"), time.Hour)
+		snapshot := cl.Snapshot(reqCtx)
+		cancel()
+
+		bgCtx := Restore(context.WithoutCancel(reqCtx), snapshot)
+		fields := logAndAssert(t, bgCtx, observed, cl, "background")
+
+		require.Equal(t, "req-1", fields[key.String()])
+		require.NotNil(t, fields[FieldContextDeadlineAt])
+		_, ok := fields[FieldContextError]
+		require.False(t, ok, "snapshot must not report the later cancellation")
+
+		fields = logAndAssert(t, Restore(context.Background(), snapshot), observed, cl, "fresh")
+		require.Equal(t, "req-1", fields[key.String()])
+	})
+
+	t.Run("logger carries captured fields", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+		snapshot := cl.Snapshot(context.WithValue(context.Background(), key, "req-1"))
+
+		snapshot.Logger().Info("snapshot-logger")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+		require.Equal(t, "test", entries[0].ContextMap()["text"])
+	})
+
+	t.Run("fields returns a copy", func(t *testing.T) {
+		cl := New(zap.NewNop(), WithValueExtractor(key))
+		snapshot := cl.Snapshot(context.WithValue(context.Background(), key, "req-1"))
+
+		fields := snapshot.Fields()
+		fields[0] = zap.String("mutated", "yes")
+
+		require.Equal(t, key.String(), snapshot.Fields()[0].Key)
+	})
+
+	t.Run("snapshot of restored context keeps captured fields", func(t *testing.T) {
+		var calls atomic.Int32
+		cl := New(zap.NewNop(), countingExtractor(&calls))
+
+		ctx := cl.Attach(context.Background())
+		snapshot := cl.Snapshot(ctx)
+
+		require.Equal(t, int32(1), calls.Load())
+		require.Len(t, snapshot.Fields(), 1)
+	})
+
+	t.Run("handles nil snapshot and context", func(t *testing.T) {
+		var snapshot *Snapshot
+		ctx := context.Background()
+
+		require.Equal(t, ctx, Restore(ctx, nil))
+		require.NotNil(t, Restore(nil, nil))
+		require.Nil(t, snapshot.Fields())
+		require.NotPanics(t, func() { snapshot.Logger().Info("nop") })
+		require.NotNil(t, New(nil).Snapshot(nil))
+	})
+}
@@ -0,0 +1,176 @@
+package contextlogger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	oddNumberErrMsg    = "Ignored key without a value."
+	nonStringKeyErrMsg = "Ignored key-value pairs with non-string keys."
+	multipleErrMsg     = "Multiple errors without a key."
+)
+
+// SugaredContextLogger is the sugared counterpart of ContextLogger. Its
+// methods take loosely typed key-value pairs like zap.SugaredLogger and add
+// the fields extracted from ctx.
+type SugaredContextLogger struct {
+	base *ContextLogger
+}
+
+// Sugar returns a SugaredContextLogger sharing the logger and extractors.
+func (c *ContextLogger) Sugar() *SugaredContextLogger {
+	return &SugaredContextLogger{base: c}
+}
+
+// Desugar returns the ContextLogger the sugared logger wraps.
+func (s *SugaredContextLogger) Desugar() *ContextLogger {
+	return s.base
+}
+
+// Ctx returns the sugared underlying logger bound to ctx; see
+// ContextLogger.CtxSugar.
+func (s *SugaredContextLogger) Ctx(ctx context.Context) *zap.SugaredLogger {
+	return s.base.CtxSugar(ctx)
+}
+
+// Debugw logs a message at DebugLevel with the key-value pairs and the fields
+// extracted from ctx.
+func (s *SugaredContextLogger) Debugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.DebugLevel, msg, keysAndValues)
+}
+
+// Infow logs a message at InfoLevel with the key-value pairs and the fields
+// extracted from ctx.
+func (s *SugaredContextLogger) Infow(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.InfoLevel, msg, keysAndValues)
+}
+
+// Warnw logs a message at WarnLevel with the key-value pairs and the fields
+// extracted from ctx.
+func (s *SugaredContextLogger) Warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.WarnLevel, msg, keysAndValues)
+}
+
+// Errorw logs a message at ErrorLevel with the key-value pairs and the fields
+// extracted from ctx.
+func (s *SugaredContextLogger) Errorw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.ErrorLevel, msg, keysAndValues)
+}
+
+// DPanicw logs a message at DPanicLevel with the key-value pairs and the
+// fields extracted from ctx. A development logger then panics.
+func (s *SugaredContextLogger) DPanicw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.DPanicLevel, msg, keysAndValues)
+}
+
+// Panicw logs a message at PanicLevel with the key-value pairs and the fields
+// extracted from ctx, then panics.
+func (s *SugaredContextLogger) Panicw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.PanicLevel, msg, keysAndValues)
+}
+
+// Fatalw logs a message at FatalLevel with the key-value pairs and the fields
+// extracted from ctx, then calls os.Exit(1).
+func (s *SugaredContextLogger) Fatalw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	s.log(ctx, zap.FatalLevel, msg, keysAndValues)
+}
+
+// log must be called directly by a level method so that the caller skip of
+// the checker logger points at their caller.
+func (s *SugaredContextLogger) log(
+	ctx context.Context,
+	lvl zapcore.Level,
+	msg string,
+	keysAndValues []interface{},
+) {
+	ce := s.base.deferExtraction(ctx, s.base.checker.Check(lvl, msg))
+	if ce != nil {
+		ce.Write(s.sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// sweetenFields converts key-value pairs to fields with the same rules as
+// zap.SugaredLogger, reporting malformed pairs as separate entries.
+func (s *SugaredContextLogger) sweetenFields(ctx context.Context, args []interface{}) []zap.Field {
+	if len(args) == 0 {
+		return nil
+	}
+
+	var (
+		fields    = make([]zap.Field, 0, len(args))
+		invalid   invalidPairs
+		seenError bool
+	)
+
+	for i := 0; i < len(args); {
+		if f, ok := args[i].(zap.Field); ok {
+			fields = append(fields, f)
+			i++
+
+			continue
+		}
+
+		if err, ok := args[i].(error); ok {
+			if !seenError {
+				seenError = true
+				fields = append(fields, zap.Error(err))
+			} else {
+				s.base.Error(ctx, multipleErrMsg, zap.Error(err))
+			}
+
+			i++
+
+			continue
+		}
+
+		if i == len(args)-1 {
+			s.base.Error(ctx, oddNumberErrMsg, zap.Any("ignored", args[i]))
+			break
+		}
+
+		key, val := args[i], args[i+1]
+		if keyStr, ok := key.(string); ok {
+			fields = append(fields, zap.Any(keyStr, val))
+		} else {
+			invalid = append(invalid, invalidPair{position: i, key: key, value: val})
+		}
+
+		i += 2
+	}
+
+	if len(invalid) > 0 {
+		s.base.Error(ctx, nonStringKeyErrMsg, zap.Array("invalid", invalid))
+	}
+
+	return fields
+}
+
+type invalidPair struct {
+	position   int
+	key, value interface{}
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (p invalidPair) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("position", int64(p.position))
+	zap.Any("key", p.key).AddTo(enc)
+	zap.Any("value", p.value).AddTo(enc)
+
+	return nil
+}
+
+type invalidPairs []invalidPair
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (ps invalidPairs) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := range ps {
+		if err := enc.AppendObject(ps[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package contextlogger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestContextLogger_CtxSugar(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+
+	t.Run("adds extracted fields", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		cl.CtxSugar(ctx).Infow("sugared", "status", 200)
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+		require.Equal(t, int64(200), entries[0].ContextMap()["status"])
+	})
+
+	t.Run("handles nil context and no extractors", func(t *testing.T) {
+		logger, observed := newTestLogger()
+
+		New(logger).CtxSugar(nil).Info("plain")
+		New(logger, WithValueExtractor(key)).CtxSugar(nil).Info("nil-context")
+
+		require.Len(t, observed.TakeAll(), 2)
+	})
+}
+
+func TestSugaredContextLogger(t *testing.T) {
+	key := contextKeyString("request_id")
+	ctx := context.WithValue(context.Background(), key, "req-1")
+
+	t.Run("logs key-value pairs at each level", func(t *testing.T) {
+		core, observed := observer.New(zap.DebugLevel)
+		s := New(zap.New(core), WithValueExtractor(key)).Sugar()
+
+		logs := []struct {
+			level zapcore.Level
+			log   func(context.Context, string, ...interface{})
+		}{
+			{zap.DebugLevel, s.Debugw},
+			{zap.InfoLevel, s.Infow},
+			{zap.WarnLevel, s.Warnw},
+			{zap.ErrorLevel, s.Errorw},
+			{zap.DPanicLevel, s.DPanicw},
+		}
+
+		for _, l := range logs {
+			l.log(ctx, "sugared", "status", 200, zap.String("typed", "yes"))
+
+			entries := observed.TakeAll()
+			require.Len(t, entries, 1)
+			require.Equal(t, l.level, entries[0].Level)
+			fields := entries[0].ContextMap()
+			require.Equal(t, "req-1", fields[key.String()])
+			require.Equal(t, int64(200), fields["status"])
+			require.Equal(t, "yes", fields["typed"])
+		}
+	})
+
+	t.Run("skips extractors and pairs for disabled levels", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		var calls atomic.Int32
+		s := New(logger, countingExtractor(&calls)).Sugar()
+
+		s.Debugw(ctx, "dropped", "dangling")
+
+		require.Zero(t, calls.Load())
+		require.Empty(t, observed.TakeAll())
+	})
+
+	t.Run("reports the caller of the level method", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		s := New(zap.New(core, zap.AddCaller()), WithValueExtractor(key)).Sugar()
+
+		s.Infow(ctx, "caller")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "sugar_test.go", filepath.Base(entries[0].Caller.File))
+	})
+
+	t.Run("reports malformed pairs like zap", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		s := New(logger, WithValueExtractor(key)).Sugar()
+
+		s.Infow(ctx, "malformed", errors.New("first"), errors.New("second"), 1, "non-string", "dangling")
+
+		entries := observed.TakeAll()
+		require.Len(t, entries, 4)
+		require.Equal(t, multipleErrMsg, entries[0].Message)
+		require.Equal(t, oddNumberErrMsg, entries[1].Message)
+		require.Equal(t, nonStringKeyErrMsg, entries[2].Message)
+		require.Equal(t, "req-1", entries[2].ContextMap()[key.String()])
+		require.Equal(t, "malformed", entries[3].Message)
+		require.Equal(t, "first", entries[3].ContextMap()["error"])
+	})
+
+	t.Run("panics after writing", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		s := New(logger).Sugar()
+
+		require.Panics(t, func() { s.Panicw(ctx, "panic") })
+		require.Len(t, observed.TakeAll(), 1)
+	})
+
+	t.Run("runs fatal hook after writing", func(t *testing.T) {
+		core, observed := observer.New(zap.InfoLevel)
+		s := New(zap.New(core, zap.WithFatalHook(zapcore.WriteThenPanic))).Sugar()
+
+		require.Panics(t, func() { s.Fatalw(ctx, "fatal") })
+		require.Len(t, observed.TakeAll(), 1)
+	})
+
+	t.Run("round-trips through Desugar", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValueExtractor(key))
+
+		require.Same(t, cl, cl.Sugar().Desugar())
+
+		cl.Sugar().Ctx(ctx).Info("ctx")
+		entries := observed.TakeAll()
+		require.Len(t, entries, 1)
+		require.Equal(t, "req-1", entries[0].ContextMap()[key.String()])
+	})
+}
@@ -0,0 +1,80 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ValueOption configures an extractor created by WithValue.
+type ValueOption func(*valueConfig)
+
+type valueConfig struct {
+	name       string
+	format     func(name string, value any) zap.Field
+	defaultVal any
+}
+
+// ValueName sets the field name, independent of the key's string
+// representation.
+func ValueName(name string) ValueOption {
+	return func(c *valueConfig) {
+		c.name = name
+	}
+}
+
+// ValueFormatter sets the function that turns the value into a field. It is
+// also applied to the default value.
+func ValueFormatter(format func(name string, value any) zap.Field) ValueOption {
+	return func(c *valueConfig) {
+		c.format = format
+	}
+}
+
+// ValueDefault sets a value to log when the context has no non-nil value for
+// the key, so "never set" is distinguishable from "not extracted".
+func ValueDefault(value any) ValueOption {
+	return func(c *valueConfig) {
+		c.defaultVal = value
+	}
+}
+
+// WithValue extracts the context value stored under key. The field is named by
+// ValueName, or else by the key's String method, or else by fmt.Sprint(key).
+// Without ValueFormatter, values are logged like WithValueExtractor does.
+// Missing or nil values are skipped unless ValueDefault is set.
+func WithValue[K comparable](key K, opts ...ValueOption) ContextExtractor {
+	var cfg valueConfig
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.name == "" {
+		if stringer, ok := any(key).(fmt.Stringer); ok {
+			cfg.name = stringer.String()
+		} else {
+			cfg.name = fmt.Sprint(key)
+		}
+	}
+
+	return func(ctx context.Context) []zap.Field {
+		val := ctx.Value(key)
+		if val == nil {
+			val = cfg.defaultVal
+		}
+
+		if val == nil {
+			return nil
+		}
+
+		if cfg.format != nil {
+			return []zap.Field{cfg.format(cfg.name, val)}
+		}
+
+		return appendValue(nil, cfg.name, val)
+	}
+}
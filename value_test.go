@@ -0,0 +1,75 @@
+package contextlogger
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type plainContextKey struct{ id int }
+
+func TestWithValue(t *testing.T) {
+	key := contextKeyString("request_id")
+
+	t.Run("names field after the key by default", func(t *testing.T) {
+		extract := WithValue(key)
+
+		ctx := context.WithValue(context.Background(), key, "req-1")
+		require.Equal(t, []zap.Field{zap.Any("request_id", "req-1")}, extract(ctx))
+		require.Nil(t, extract(context.Background()))
+	})
+
+	t.Run("uses custom field name", func(t *testing.T) {
+		extract := WithValue(plainContextKey{id: 1}, ValueName("trace_id"))
+
+		ctx := context.WithValue(context.Background(), plainContextKey{id: 1}, "abc")
+		require.Equal(t, []zap.Field{zap.Any("trace_id", "abc")}, extract(ctx))
+	})
+
+	t.Run("falls back to formatted key name", func(t *testing.T) {
+		extract := WithValue(plainContextKey{id: 2})
+
+		ctx := context.WithValue(context.Background(), plainContextKey{id: 2}, "abc")
+		require.Equal(t, []zap.Field{zap.Any("{2}", "abc")}, extract(ctx))
+	})
+
+	t.Run("emits default when absent or nil", func(t *testing.T) {
+		logger, observed := newTestLogger()
+		cl := New(logger, WithValue(key, ValueDefault("none")))
+
+		fields := logAndAssert(t, context.Background(), observed, cl, "absent")
+		require.Equal(t, "none", fields["request_id"])
+
+		fields = logAndAssert(t, context.WithValue(context.Background(), key, nil), observed, cl, "nil")
+		require.Equal(t, "none", fields["request_id"])
+
+		fields = logAndAssert(t, context.WithValue(context.Background(), key, "req-1"), observed, cl, "present")
+		require.Equal(t, "req-1", fields["request_id"])
+	})
+
+	t.Run("formats values and defaults", func(t *testing.T) {
+		extract := WithValue(key,
+			ValueName("request"),
+			ValueDefault(0),
+			ValueFormatter(func(name string, value any) zap.Field {
+				return zap.String(name, fmt.Sprintf("#%v", value))
+			}),
+			nil,
+		)
+
+		ctx := context.WithValue(context.Background(), key, 7)
+		require.Equal(t, []zap.Field{zap.String("request", "#7")}, extract(ctx))
+		require.Equal(t, []zap.Field{zap.String("request", "#0")}, extract(context.Background()))
+	})
+
+	t.Run("logs self-describing values without formatter", func(t *testing.T) {
+		extract := WithValue(key)
+		tenant := testTenant{id: "t-1"}
+
+		ctx := context.WithValue(context.Background(), key, tenant)
+		require.Equal(t, tenant.LogFields(), extract(ctx))
+	})
+}